@@ -0,0 +1,105 @@
+// Command openapi3 provides tools to work with the OpenAPI 3 specifications
+// generated by goyave.dev/openapi3.
+//
+// Usage:
+//
+//	openapi3 diff [flags] <old spec> <new spec>
+//
+// The diff command compares two specs (JSON or YAML) and reports the changes,
+// classified as breaking or non-breaking for the clients of the API.
+//
+// Flags:
+//
+//	-format string
+//		output format: "markdown" or "json" (default "markdown")
+//	-fail-on-breaking
+//		exit with status 1 if breaking changes are detected
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	openapi "goyave.dev/openapi3"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(stderr, "usage: openapi3 <command> [arguments]\n\ncommands:\n  diff    compare two specs and report breaking changes")
+		return 2
+	}
+
+	switch args[0] {
+	case "diff":
+		return runDiff(args[1:], stdout, stderr)
+	default:
+		_, _ = fmt.Fprintf(stderr, "openapi3: unknown command %q\n", args[0])
+		return 2
+	}
+}
+
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "usage: openapi3 diff [flags] <old spec> <new spec>")
+		flags.PrintDefaults()
+	}
+	format := flags.String("format", "markdown", `output format: "markdown" or "json"`)
+	failOnBreaking := flags.Bool("fail-on-breaking", false, "exit with status 1 if breaking changes are detected")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	loader := openapi3.NewLoader()
+	before, err := loader.LoadFromFile(flags.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "openapi3: could not load %q: %s\n", flags.Arg(0), err)
+		return 1
+	}
+	after, err := loader.LoadFromFile(flags.Arg(1))
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "openapi3: could not load %q: %s\n", flags.Arg(1), err)
+		return 1
+	}
+
+	report, err := openapi.Diff(before, after)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "openapi3: %s\n", err)
+		return 1
+	}
+	switch *format {
+	case "markdown":
+		if _, err := fmt.Fprint(stdout, report.Markdown()); err != nil {
+			_, _ = fmt.Fprintf(stderr, "openapi3: %s\n", err)
+			return 1
+		}
+	case "json":
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			_, _ = fmt.Fprintf(stderr, "openapi3: %s\n", err)
+			return 1
+		}
+	default:
+		_, _ = fmt.Fprintf(stderr, "openapi3: unknown format %q\n", *format)
+		return 2
+	}
+
+	if *failOnBreaking && report.HasBreakingChanges() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/suite"
+)
+
+type MainTestSuite struct {
+	suite.Suite
+	oldPath string
+	newPath string
+}
+
+func (suite *MainTestSuite) writeSpec(path string, pathItem *openapi3.PathItem) {
+	spec := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "Test", Version: "0.0.0"},
+		Paths:   openapi3.Paths{"/users": pathItem},
+	}
+	b, err := spec.MarshalJSON()
+	suite.Require().NoError(err)
+	suite.Require().NoError(os.WriteFile(path, b, 0644))
+}
+
+func (suite *MainTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+	suite.oldPath = filepath.Join(dir, "old.json")
+	suite.newPath = filepath.Join(dir, "new.json")
+	suite.writeSpec(suite.oldPath, &openapi3.PathItem{
+		Get:    openapi3.NewOperation(),
+		Delete: openapi3.NewOperation(),
+	})
+	suite.writeSpec(suite.newPath, &openapi3.PathItem{
+		Get: openapi3.NewOperation(),
+	})
+}
+
+func (suite *MainTestSuite) TestDiffMarkdown() {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	suite.Equal(0, run([]string{"diff", suite.oldPath, suite.newPath}, stdout, stderr))
+	suite.Equal("## API changes\n\n### Breaking changes\n\n- `DELETE /users`: operation removed\n", stdout.String())
+	suite.Empty(stderr.String())
+}
+
+func (suite *MainTestSuite) TestDiffJSON() {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	suite.Equal(0, run([]string{"diff", "-format", "json", suite.oldPath, suite.newPath}, stdout, stderr))
+	suite.JSONEq(`{"changes":[{"operation":"DELETE /users","description":"operation removed","breaking":true}]}`, stdout.String())
+}
+
+func (suite *MainTestSuite) TestDiffFailOnBreaking() {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	suite.Equal(1, run([]string{"diff", "-fail-on-breaking", suite.oldPath, suite.newPath}, stdout, stderr))
+	suite.Equal(0, run([]string{"diff", "-fail-on-breaking", suite.newPath, suite.oldPath}, stdout, stderr))
+}
+
+func (suite *MainTestSuite) TestInvalidArguments() {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	suite.Equal(2, run([]string{}, stdout, stderr))
+	suite.Equal(2, run([]string{"unknown"}, stdout, stderr))
+	suite.Equal(2, run([]string{"diff", suite.oldPath}, stdout, stderr))
+	suite.Equal(2, run([]string{"diff", "-format", "xml", suite.oldPath, suite.newPath}, stdout, stderr))
+	suite.Equal(1, run([]string{"diff", "notafile.json", suite.newPath}, stdout, stderr))
+}
+
+func TestMainSuite(t *testing.T) {
+	suite.Run(t, new(MainTestSuite))
+}
@@ -0,0 +1,89 @@
+package openapi3
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+)
+
+// RunCommand generate an OpenAPI 3 specification based on the Router built by the
+// given route registrar, and write it to a file. The server is not started.
+// The given arguments are the command line arguments, without the program name:
+//
+//	-o string
+//		output file, "-" for the standard output (default "openapi.json")
+//	-format string
+//		"json" or "yaml", deduced from the output file extension if empty
+//	-pretty
+//		indent the JSON output
+//	-header
+//		write the generator and app versions in a header comment (YAML) or in the
+//		"x-generated-by" extension (JSON)
+//	-env string
+//		goyave environment used to load the config (GOYAVE_ENV)
+//
+// This function is meant to be called from the main function of a dedicated command
+// in your application, so the spec can be generated with "go generate" or in CI.
+func (g *Generator) RunCommand(registrar func(*goyave.Router), args []string) error {
+	flags := flag.NewFlagSet("openapi3", flag.ContinueOnError)
+	output := flags.String("o", "openapi.json", `output file, "-" for the standard output`)
+	format := flags.String("format", "", `"json" or "yaml", deduced from the output file extension if empty`)
+	pretty := flags.Bool("pretty", false, "indent the JSON output")
+	header := flags.Bool("header", false, `write the generator and app versions in a header comment (YAML) or in the "x-generated-by" extension (JSON)`)
+	env := flags.String("env", "", "goyave environment used to load the config (GOYAVE_ENV)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *format == "" {
+		*format = formatFromPath(*output)
+	}
+	f := Format(*format)
+	switch {
+	case f == FormatJSON && *pretty:
+		f = FormatJSONIndent
+	case f != FormatJSON && f != FormatYAML:
+		return fmt.Errorf("openapi3: unknown format %q", *format)
+	}
+
+	if *env != "" {
+		if err := os.Setenv("GOYAVE_ENV", *env); err != nil {
+			return err
+		}
+		if err := config.Load(); err != nil {
+			return err
+		}
+	} else if err := loadConfig(); err != nil {
+		return err
+	}
+
+	router := goyave.NewRouter()
+	registrar(router)
+	spec, err := g.GenerateE(router)
+	if err != nil {
+		return err
+	}
+
+	opts := []EncodeOption{}
+	if *header {
+		opts = append(opts, WithHeader())
+	}
+	if *output == "-" {
+		return Encode(spec, os.Stdout, f, opts...)
+	}
+	return WriteSpec(spec, *output, f, opts...)
+}
+
+func formatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	default:
+		return "json"
+	}
+}
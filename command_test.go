@@ -0,0 +1,82 @@
+package openapi3
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+)
+
+type CommandTestSuite struct {
+	goyave.TestSuite
+}
+
+func registerTestRoutes(router *goyave.Router) {
+	router.Get("/users", HandlerTest)
+}
+
+func (suite *CommandTestSuite) TestRunCommandJSON() {
+	path := filepath.Join(suite.T().TempDir(), "openapi.json")
+	suite.Require().NoError(NewGenerator().RunCommand(registerTestRoutes, []string{"-o", path}))
+
+	b, err := os.ReadFile(path)
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(string(b), `{"components":`))
+	suite.Contains(string(b), `"/users":`)
+	suite.True(strings.HasSuffix(string(b), "}\n"))
+
+	suite.Require().NoError(NewGenerator().RunCommand(registerTestRoutes, []string{"-o", path, "-pretty"}))
+	b, err = os.ReadFile(path)
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(string(b), "{\n  \"components\":"))
+}
+
+func (suite *CommandTestSuite) TestRunCommandYAML() {
+	dir := suite.T().TempDir()
+	path := filepath.Join(dir, "openapi.yaml")
+	suite.Require().NoError(NewGenerator().RunCommand(registerTestRoutes, []string{"-o", path}))
+
+	b, err := os.ReadFile(path)
+	suite.Require().NoError(err)
+	suite.Contains(string(b), "openapi: 3.0.0\n")
+	suite.Contains(string(b), "    /users:\n")
+
+	path = filepath.Join(dir, "spec.txt")
+	suite.Require().NoError(NewGenerator().RunCommand(registerTestRoutes, []string{"-o", path, "-format", "yaml"}))
+	b, err = os.ReadFile(path)
+	suite.Require().NoError(err)
+	suite.Contains(string(b), "openapi: 3.0.0\n")
+}
+
+func (suite *CommandTestSuite) TestRunCommandErrors() {
+	suite.Error(NewGenerator().RunCommand(registerTestRoutes, []string{"-unknown"}))
+	suite.Error(NewGenerator().RunCommand(registerTestRoutes, []string{"-format", "xml"}))
+	suite.Error(NewGenerator().RunCommand(registerTestRoutes, []string{"-o", filepath.Join(suite.T().TempDir(), "notadir", "openapi.json")}))
+}
+
+func (suite *CommandTestSuite) TestFormatFromPath() {
+	suite.Equal("json", formatFromPath("openapi.json"))
+	suite.Equal("yaml", formatFromPath("openapi.yaml"))
+	suite.Equal("yaml", formatFromPath("openapi.YML"))
+	suite.Equal("json", formatFromPath("-"))
+}
+
+func TestCommandSuite(t *testing.T) {
+	if err := config.LoadJSON(`{
+			"app": {
+				"name": "Generator"
+			},
+			"server": {
+				"protocol": "http",
+				"domain": "goyave.dev",
+				"port": 80
+			}
+		}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(CommandTestSuite))
+}
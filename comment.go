@@ -0,0 +1,103 @@
+package openapi3
+
+import (
+	"go/doc/comment"
+	"strings"
+	"unicode"
+)
+
+// convertDoc converts the given Go doc comment text to a summary, which
+// is the first sentence of the comment, and a CommonMark description
+// containing the rest of the comment.
+func convertDoc(text string) (string, string) {
+	doc := new(comment.Parser).Parse(text)
+	if len(doc.Content) == 0 {
+		return "", ""
+	}
+	paragraph, ok := doc.Content[0].(*comment.Paragraph)
+	if !ok {
+		return "", markdown(doc)
+	}
+
+	summary := firstSentence(plainText(paragraph.Text))
+	rest := splitText(paragraph.Text, len(summary))
+
+	content := doc.Content[1:]
+	if strings.TrimSpace(plainText(rest)) != "" {
+		content = append([]comment.Block{&comment.Paragraph{Text: rest}}, content...)
+	}
+	description := markdown(&comment.Doc{Content: content, Links: doc.Links})
+
+	return strings.Join(strings.Fields(summary), " "), description
+}
+
+// plainText returns the text of the given nodes, without formatting and links.
+func plainText(text []comment.Text) string {
+	b := &strings.Builder{}
+	for _, t := range text {
+		switch t := t.(type) {
+		case comment.Plain:
+			b.WriteString(string(t))
+		case comment.Italic:
+			b.WriteString(string(t))
+		case *comment.Link:
+			b.WriteString(plainText(t.Text))
+		case *comment.DocLink:
+			b.WriteString(plainText(t.Text))
+		}
+	}
+	return b.String()
+}
+
+// splitText returns the nodes of the given text after the first n bytes of its
+// plain text (see plainText). Plain and italic nodes are split, while links are
+// kept whole: a link starting in the first n bytes is dropped. The leading
+// spaces of the result are trimmed.
+func splitText(text []comment.Text, n int) []comment.Text {
+	rest := []comment.Text{}
+	offset := 0
+	for _, t := range text {
+		length := len(plainText([]comment.Text{t}))
+		switch {
+		case offset >= n:
+			rest = append(rest, t)
+		case offset+length > n:
+			switch t := t.(type) {
+			case comment.Plain:
+				rest = append(rest, t[n-offset:])
+			case comment.Italic:
+				rest = append(rest, t[n-offset:])
+			}
+		}
+		offset += length
+	}
+
+	for len(rest) > 0 {
+		switch t := rest[0].(type) {
+		case comment.Plain:
+			rest[0] = comment.Plain(strings.TrimLeftFunc(string(t), unicode.IsSpace))
+		case comment.Italic:
+			rest[0] = comment.Italic(strings.TrimLeftFunc(string(t), unicode.IsSpace))
+		}
+		if rest[0] == comment.Plain("") || rest[0] == comment.Italic("") {
+			rest = rest[1:]
+			continue
+		}
+		break
+	}
+	return rest
+}
+
+// goDocToMarkdown converts the given Go doc comment text to CommonMark.
+func goDocToMarkdown(text string) string {
+	return markdown(new(comment.Parser).Parse(text))
+}
+
+func markdown(doc *comment.Doc) string {
+	printer := &comment.Printer{
+		HeadingLevel:   3,
+		HeadingID:      func(*comment.Heading) string { return "" },
+		DocLinkBaseURL: "https://pkg.go.dev",
+	}
+	return strings.TrimSpace(string(printer.Markdown(doc)))
+}
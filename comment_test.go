@@ -0,0 +1,86 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CommentTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CommentTestSuite) TestConvertDoc() {
+	summary, description := convertDoc("")
+	suite.Empty(summary)
+	suite.Empty(description)
+
+	summary, description = convertDoc("HandlerTest a test handler for AST reading")
+	suite.Equal("HandlerTest a test handler for AST reading", summary)
+	suite.Empty(description)
+
+	summary, description = convertDoc("Update updates\nthe user. Only admins can do this,\ne.g. for moderation.\n\nMore details.\n")
+	suite.Equal("Update updates the user.", summary)
+	suite.Equal("Only admins can do this, e.g. for moderation.\n\nMore details.", description)
+}
+
+func (suite *CommentTestSuite) TestConvertDocLinks() {
+	doc := `Update updates the user. See [RFC 7807] for the
+*details*, or https://goyave.dev.
+
+[RFC 7807]: https://www.rfc-editor.org/rfc/rfc7807
+`
+	summary, description := convertDoc(doc)
+	suite.Equal("Update updates the user.", summary)
+	suite.Equal("See [RFC 7807](https://www.rfc-editor.org/rfc/rfc7807) for the \\*details\\*, or [https://goyave.dev](https://goyave.dev).", description)
+
+	// The summary contains a link
+	summary, description = convertDoc("Update follows [RFC 7807]. More details.\n\n[RFC 7807]: https://www.rfc-editor.org/rfc/rfc7807\n")
+	suite.Equal("Update follows RFC 7807.", summary)
+	suite.Equal("More details.", description)
+}
+
+func (suite *CommentTestSuite) TestConvertDocMarkdown() {
+	doc := `Update updates a user.
+
+The request body looks like this:
+
+	{"name": "John"}
+
+Steps:
+  - first
+  - second
+
+# Errors
+
+See [RFC 7807].
+
+[RFC 7807]: https://www.rfc-editor.org/rfc/rfc7807
+`
+	summary, description := convertDoc(doc)
+	suite.Equal("Update updates a user.", summary)
+	expected := "The request body looks like this:\n\n\t{\"name\": \"John\"}\n\nSteps:\n\n  - first\n  - second\n\n### Errors\n\nSee [RFC 7807](https://www.rfc-editor.org/rfc/rfc7807)."
+	suite.Equal(expected, description)
+}
+
+func (suite *CommentTestSuite) TestConvertDocNoParagraph() {
+	summary, description := convertDoc("# Usage\n\nSome text.\n")
+	suite.Empty(summary)
+	suite.Equal("### Usage\n\nSome text.", description)
+}
+
+func (suite *CommentTestSuite) TestGoDocToMarkdown() {
+	suite.Empty(goDocToMarkdown(""))
+	suite.Equal("Package user handles users.\n\n### Usage\n\nSee the \\*docs\\*.", goDocToMarkdown("Package user handles users.\n\n# Usage\n\nSee the *docs*.\n"))
+}
+
+func (suite *CommentTestSuite) TestFirstSentence() {
+	suite.Equal("First sentence.", firstSentence("First sentence. Second sentence."))
+	suite.Equal("Made in the U.S. in 2023.", firstSentence("Made in the U.S. in 2023. Second sentence."))
+	suite.Equal("Built by J. Doe.", firstSentence("Built by J. Doe. Second sentence."))
+	suite.Equal("No period", firstSentence("No period"))
+}
+
+func TestCommentSuite(t *testing.T) {
+	suite.Run(t, new(CommentTestSuite))
+}
@@ -0,0 +1,12 @@
+package openapi3
+
+import (
+	"reflect"
+
+	"goyave.dev/goyave/v4/config"
+)
+
+func init() {
+	config.Register("openapi.ui.enabled", config.Entry{Value: true, Type: reflect.Bool})
+	config.Register("openapi.ui.path", config.Entry{Value: "/openapi", Type: reflect.String})
+}
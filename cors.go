@@ -0,0 +1,94 @@
+package openapi3
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/cors"
+)
+
+var corsOptionsType = reflect.TypeOf((*cors.Options)(nil))
+
+// corsOptions returns the CORS options of the given router, or of its closest parent
+// with CORS options. Returns nil if CORS is not enabled.
+func corsOptions(router *goyave.Router) *cors.Options {
+	for ; router != nil; router = router.GetParent() {
+		// goyave doesn't expose the CORS options of a router: they are read from
+		// the unexported "corsOptions" field. TestCORSOptions fails if the field changes.
+		field := reflect.ValueOf(router).Elem().FieldByName("corsOptions")
+		if !field.IsValid() || field.Type() != corsOptionsType {
+			return nil
+		}
+		options := reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Interface().(*cors.Options)
+		if options != nil {
+			return options
+		}
+	}
+	return nil
+}
+
+// convertCORS documents the CORS response headers on the responses of the given operation.
+// OPTIONS operations are documented as preflight requests, unless the options
+// let them pass through to the route's handler.
+func convertCORS(method string, op *openapi3.Operation, options *cors.Options) {
+	preflight := isPreflight(method, options)
+	if preflight {
+		preflight := openapi3.NewResponse().WithDescription("CORS preflight response.")
+		preflight.Headers = openapi3.Headers{
+			"Access-Control-Allow-Methods": newHeader("Allowed methods: `"+strings.Join(options.AllowedMethods, ", ")+"`.", openapi3.NewStringSchema()),
+		}
+		if len(options.AllowedHeaders) == 0 {
+			preflight.Headers["Access-Control-Allow-Headers"] = newHeader("The requested headers.", openapi3.NewStringSchema())
+		} else {
+			preflight.Headers["Access-Control-Allow-Headers"] = newHeader("Allowed headers: `"+strings.Join(options.AllowedHeaders, ", ")+"`.", openapi3.NewStringSchema())
+		}
+		if options.MaxAge > 0 {
+			preflight.Headers["Access-Control-Max-Age"] = newHeader(fmt.Sprintf("`%d`", int(options.MaxAge.Seconds())), openapi3.NewIntegerSchema())
+		}
+		op.Responses = openapi3.Responses{
+			"204": &openapi3.ResponseRef{Value: preflight},
+		}
+	}
+
+	for _, response := range op.Responses {
+		if response.Value == nil {
+			continue
+		}
+		if response.Value.Headers == nil {
+			response.Value.Headers = make(openapi3.Headers, 3)
+		}
+		if len(options.AllowedOrigins) == 0 || options.AllowedOrigins[0] == "*" {
+			response.Value.Headers["Access-Control-Allow-Origin"] = newHeader("`*`", openapi3.NewStringSchema())
+		} else {
+			response.Value.Headers["Access-Control-Allow-Origin"] = newHeader("The request's origin if it is allowed: `"+strings.Join(options.AllowedOrigins, "`, `")+"`.", openapi3.NewStringSchema())
+		}
+		if options.AllowCredentials {
+			response.Value.Headers["Access-Control-Allow-Credentials"] = newHeader("`true`", openapi3.NewStringSchema())
+		}
+		if len(options.ExposedHeaders) != 0 && !preflight {
+			response.Value.Headers["Access-Control-Expose-Headers"] = newHeader("`"+strings.Join(options.ExposedHeaders, ", ")+"`", openapi3.NewStringSchema())
+		}
+	}
+}
+
+// isPreflight returns true if the operation of the given method is a CORS preflight
+// request, handled by the CORS middleware instead of the route's handler.
+func isPreflight(method string, options *cors.Options) bool {
+	return method == http.MethodOptions && !options.OptionsPassthrough
+}
+
+func newHeader(description string, schema *openapi3.Schema) *openapi3.HeaderRef {
+	return &openapi3.HeaderRef{
+		Value: &openapi3.Header{
+			Parameter: openapi3.Parameter{
+				Description: description,
+				Schema:      schema.NewRef(),
+			},
+		},
+	}
+}
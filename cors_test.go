@@ -0,0 +1,165 @@
+package openapi3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+	"goyave.dev/goyave/v4/cors"
+	"goyave.dev/goyave/v4/validation"
+)
+
+type CORSTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *CORSTestSuite) makeOptions() *cors.Options {
+	return &cors.Options{
+		AllowedOrigins:   []string{"https://example.org", "https://goyave.dev"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Content-Type"},
+		ExposedHeaders:   []string{"X-Total"},
+		MaxAge:           time.Hour,
+		AllowCredentials: true,
+	}
+}
+
+func (suite *CORSTestSuite) TestCORSOptions() {
+	router := goyave.NewRouter()
+	suite.Nil(corsOptions(router))
+	suite.Nil(corsOptions(nil))
+
+	options := suite.makeOptions()
+	router.CORS(options)
+	// Fails if goyave's Router doesn't store its CORS options in the "corsOptions" field anymore.
+	suite.Same(options, corsOptions(router), "the CORS options of goyave's Router cannot be read anymore")
+	suite.Same(options, corsOptions(router.Subrouter("/sub")))
+}
+
+func (suite *CORSTestSuite) TestGenerateCORSHeaders() {
+	router := goyave.NewRouter()
+	router.CORS(suite.makeOptions())
+	router.Get("/users", HandlerTest)
+	router.Subrouter("/products").Post("/", HandlerTest)
+
+	spec := NewGenerator().Generate(router)
+	pathItem := spec.Paths["/users"]
+	suite.Nil(pathItem.Head)
+	suite.Nil(pathItem.Options)
+
+	headers := pathItem.Get.Responses["default"].Value.Headers
+	suite.Contains(headers["Access-Control-Allow-Origin"].Value.Description, "https://goyave.dev")
+	suite.Contains(headers, "Access-Control-Allow-Credentials")
+	suite.Equal("`X-Total`", headers["Access-Control-Expose-Headers"].Value.Description)
+	suite.Contains(spec.Paths["/products"].Post.Responses["default"].Value.Headers, "Access-Control-Allow-Origin")
+}
+
+func (suite *CORSTestSuite) TestGenerateHeadAndOptions() {
+	router := goyave.NewRouter()
+	router.CORS(suite.makeOptions())
+	router.Get("/users", HandlerTest)
+
+	generator := NewGenerator()
+	generator.IncludeHeadAndOptions = true
+	spec := generator.Generate(router)
+	pathItem := spec.Paths["/users"]
+	suite.Require().NotNil(pathItem.Head)
+	suite.Require().NotNil(pathItem.Options)
+	suite.Equal("openapi3.HandlerTest", pathItem.Get.OperationID)
+	suite.Equal("openapi3.HandlerTest.head", pathItem.Head.OperationID)
+	suite.Equal("openapi3.HandlerTest.options", pathItem.Options.OperationID)
+
+	suite.Len(pathItem.Options.Responses, 1)
+	preflight := pathItem.Options.Responses["204"].Value
+	suite.Equal("Allowed methods: `GET, POST`.", preflight.Headers["Access-Control-Allow-Methods"].Value.Description)
+	suite.Equal("Allowed headers: `Content-Type`.", preflight.Headers["Access-Control-Allow-Headers"].Value.Description)
+	suite.Equal("`3600`", preflight.Headers["Access-Control-Max-Age"].Value.Description)
+	suite.Contains(preflight.Headers, "Access-Control-Allow-Origin")
+	suite.NotContains(preflight.Headers, "Access-Control-Expose-Headers")
+}
+
+func (suite *CORSTestSuite) TestGenerateOptionsPassthrough() {
+	options := cors.Default()
+	options.OptionsPassthrough = true
+	router := goyave.NewRouter()
+	router.CORS(options)
+	router.Get("/users", HandlerTest)
+
+	generator := NewGenerator()
+	generator.IncludeHeadAndOptions = true
+	spec := generator.Generate(router)
+	responses := spec.Paths["/users"].Options.Responses
+	suite.Contains(responses, "default")
+	suite.Equal("`*`", responses["default"].Value.Headers["Access-Control-Allow-Origin"].Value.Description)
+}
+
+func (suite *CORSTestSuite) TestGenerateHeadWithoutCORS() {
+	router := goyave.NewRouter()
+	router.Get("/users", HandlerTest)
+
+	generator := NewGenerator()
+	generator.IncludeHeadAndOptions = true
+	spec := generator.Generate(router)
+	pathItem := spec.Paths["/users"]
+	suite.NotNil(pathItem.Head)
+	suite.Nil(pathItem.Options)
+	suite.Empty(pathItem.Head.Responses["default"].Value.Headers)
+}
+
+func (suite *CORSTestSuite) TestGeneratePreflightWithoutRules() {
+	router := goyave.NewRouter()
+	router.CORS(suite.makeOptions())
+	router.Get("/users", HandlerTest).Validate(&validation.Rules{
+		Fields: validation.FieldMap{
+			"page": &validation.Field{Rules: []*validation.Rule{{Name: "integer"}}},
+		},
+	})
+
+	generator := NewGenerator()
+	generator.IncludeHeadAndOptions = true
+	spec := generator.Generate(router)
+	pathItem := spec.Paths["/users"]
+	suite.Require().NotNil(pathItem.Options)
+	suite.False(hasParameter(pathItem.Options, openapi3.ParameterInQuery, "page", spec))
+	suite.NotContains(pathItem.Options.Responses, "422")
+	suite.True(hasParameter(pathItem.Get, openapi3.ParameterInQuery, "page", spec))
+	suite.Contains(pathItem.Get.Responses, "422")
+}
+
+func (suite *CORSTestSuite) TestGenerateHeadWithoutBody() {
+	router := goyave.NewRouter()
+	router.Get("/users", HandlerTest).Validate(&validation.Rules{
+		Fields: validation.FieldMap{
+			"page": &validation.Field{Rules: []*validation.Rule{{Name: "integer"}}},
+		},
+	})
+
+	generator := NewGenerator()
+	generator.IncludeHeadAndOptions = true
+	spec := generator.Generate(router)
+	pathItem := spec.Paths["/users"]
+	suite.Require().NotNil(pathItem.Head)
+	suite.Require().Contains(pathItem.Head.Responses, "422")
+	suite.Nil(pathItem.Head.Responses["422"].Value.Content)
+	suite.True(hasParameter(pathItem.Head, openapi3.ParameterInQuery, "page", spec))
+	suite.NotNil(pathItem.Get.Responses["422"].Value.Content)
+}
+
+func TestCORSSuite(t *testing.T) {
+	if err := config.LoadJSON(`{
+			"app": {
+				"name": "Generator"
+			},
+			"server": {
+				"protocol": "http",
+				"domain": "goyave.dev",
+				"port": 80
+			}
+		}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(CORSTestSuite))
+}
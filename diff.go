@@ -0,0 +1,585 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Change a single difference between two specs, detected by Diff.
+type Change struct {
+	// Operation the method and path of the affected operation, for example "GET /users".
+	Operation string `json:"operation"`
+
+	// Location the affected element of the operation, for example `query parameter "page"`.
+	// Empty if the change affects the whole operation.
+	Location string `json:"location,omitempty"`
+
+	// Description a human-readable description of the change.
+	Description string `json:"description"`
+
+	// Breaking true if the change can break the existing clients of the API.
+	Breaking bool `json:"breaking"`
+}
+
+// String returns a human-readable representation of the change.
+func (c *Change) String() string {
+	if c.Location == "" {
+		return fmt.Sprintf("%s: %s", c.Operation, c.Description)
+	}
+	return fmt.Sprintf("%s: %s: %s", c.Operation, c.Location, c.Description)
+}
+
+// DiffReport the result of the comparison of two specs.
+type DiffReport struct {
+	Changes []*Change `json:"changes"`
+}
+
+// HasBreakingChanges returns true if at least one of the changes is breaking.
+func (r *DiffReport) HasBreakingChanges() bool {
+	for _, c := range r.Changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Breaking returns the breaking changes.
+func (r *DiffReport) Breaking() []*Change {
+	return r.filter(true)
+}
+
+// NonBreaking returns the non-breaking changes.
+func (r *DiffReport) NonBreaking() []*Change {
+	return r.filter(false)
+}
+
+func (r *DiffReport) filter(breaking bool) []*Change {
+	changes := []*Change{}
+	for _, c := range r.Changes {
+		if c.Breaking == breaking {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// Markdown returns a Markdown changelog of the changes, with breaking changes first.
+func (r *DiffReport) Markdown() string {
+	if len(r.Changes) == 0 {
+		return "## API changes\n\nNo API changes.\n"
+	}
+	builder := &strings.Builder{}
+	builder.WriteString("## API changes\n")
+	writeMarkdownChanges(builder, "Breaking changes", r.Breaking())
+	writeMarkdownChanges(builder, "Non-breaking changes", r.NonBreaking())
+	return builder.String()
+}
+
+func writeMarkdownChanges(builder *strings.Builder, title string, changes []*Change) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintf(builder, "\n### %s\n\n", title)
+	for _, c := range changes {
+		fmt.Fprintf(builder, "- `%s`: ", c.Operation)
+		if c.Location != "" {
+			fmt.Fprintf(builder, "%s: ", c.Location)
+		}
+		builder.WriteString(c.Description)
+		builder.WriteString("\n")
+	}
+}
+
+// Diff compare two specs and classify their differences as breaking or non-breaking
+// for the clients of the API. Operations, parameters, request bodies and responses
+// are compared.
+//
+// Removing an input (a parameter, a request body field or the request body itself)
+// is always breaking: clients still sending it would be silently ignored. The following
+// changes are breaking too:
+//   - an operation is removed
+//   - a parameter or a field is added as required, or an optional one becomes required
+//   - the type or format of a parameter or a field changes
+//   - a constraint is tightened (for example a lower maximum or a higher minimum)
+//   - an enum value is removed
+//   - a request body media type is removed
+//   - a value is not nullable anymore
+//   - a response, a response media type or a response field is removed, or the type
+//     of a response field changes
+//
+// The refs of both specs are resolved against their components before the comparison,
+// so specs returned by Generator.Generate can be compared directly. Returns an error
+// if a ref cannot be resolved.
+func Diff(before, after *openapi3.T) (*DiffReport, error) {
+	before, err := resolveRefs(before)
+	if err != nil {
+		return nil, fmt.Errorf("openapi3: could not resolve refs of the old spec: %w", err)
+	}
+	after, err = resolveRefs(after)
+	if err != nil {
+		return nil, fmt.Errorf("openapi3: could not resolve refs of the new spec: %w", err)
+	}
+
+	d := &differ{
+		report:    &DiffReport{Changes: []*Change{}},
+		comparing: make(map[schemaPair]struct{}),
+	}
+
+	for _, path := range sortedKeys(before.Paths, after.Paths) {
+		beforeItem, afterItem := before.Paths[path], after.Paths[path]
+		beforeOps, afterOps := operations(beforeItem), operations(afterItem)
+		for _, method := range sortedKeys(beforeOps, afterOps) {
+			d.operation = method + " " + path
+			beforeOp, inBefore := beforeOps[method]
+			afterOp, inAfter := afterOps[method]
+			switch {
+			case !inAfter:
+				d.add(true, "", "operation removed")
+			case !inBefore:
+				d.add(false, "", "operation added")
+			default:
+				d.diffParameters(
+					append(append(openapi3.Parameters{}, beforeItem.Parameters...), beforeOp.Parameters...),
+					append(append(openapi3.Parameters{}, afterItem.Parameters...), afterOp.Parameters...),
+				)
+				d.diffRequestBody(beforeOp.RequestBody, afterOp.RequestBody)
+				d.diffResponses(beforeOp.Responses, afterOp.Responses)
+			}
+		}
+	}
+
+	return d.report, nil
+}
+
+// resolveRefs returns a copy of the given spec whose refs are resolved against
+// its components.
+func resolveRefs(spec *openapi3.T) (*openapi3.T, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	return openapi3.NewLoader().LoadFromData(data)
+}
+
+type differ struct {
+	report    *DiffReport
+	comparing map[schemaPair]struct{}
+	operation string
+}
+
+// schemaPair a pair of schemas being compared, used to stop the comparison of
+// recursive schemas.
+type schemaPair struct {
+	before, after *openapi3.Schema
+	output        bool
+}
+
+// enter returns false if the given schemas are already being compared
+// higher in the tree, meaning the schemas are recursive.
+func (d *differ) enter(pair schemaPair) bool {
+	if _, ok := d.comparing[pair]; ok {
+		return false
+	}
+	d.comparing[pair] = struct{}{}
+	return true
+}
+
+func (d *differ) leave(pair schemaPair) {
+	delete(d.comparing, pair)
+}
+
+func (d *differ) add(breaking bool, location, format string, args ...interface{}) {
+	d.report.Changes = append(d.report.Changes, &Change{
+		Operation:   d.operation,
+		Location:    location,
+		Description: fmt.Sprintf(format, args...),
+		Breaking:    breaking,
+	})
+}
+
+func operations(pathItem *openapi3.PathItem) map[string]*openapi3.Operation {
+	if pathItem == nil {
+		return map[string]*openapi3.Operation{}
+	}
+	return pathItem.Operations()
+}
+
+func (d *differ) diffParameters(before, after openapi3.Parameters) {
+	type key struct{ in, name string }
+	beforeParams := make(map[key]*openapi3.Parameter, len(before))
+	afterParams := make(map[key]*openapi3.Parameter, len(after))
+	keys := []key{}
+	for _, p := range before {
+		if p.Value != nil {
+			k := key{p.Value.In, p.Value.Name}
+			beforeParams[k] = p.Value
+			keys = append(keys, k)
+		}
+	}
+	for _, p := range after {
+		if p.Value != nil {
+			k := key{p.Value.In, p.Value.Name}
+			if _, ok := beforeParams[k]; !ok {
+				keys = append(keys, k)
+			}
+			afterParams[k] = p.Value
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].in != keys[j].in {
+			return keys[i].in < keys[j].in
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	for _, k := range keys {
+		location := fmt.Sprintf("%s parameter %q", k.in, k.name)
+		beforeParam, inBefore := beforeParams[k]
+		afterParam, inAfter := afterParams[k]
+		switch {
+		case !inAfter:
+			d.add(true, location, "parameter removed")
+		case !inBefore:
+			if afterParam.Required {
+				d.add(true, location, "required parameter added")
+			} else {
+				d.add(false, location, "optional parameter added")
+			}
+		default:
+			if !beforeParam.Required && afterParam.Required {
+				d.add(true, location, "parameter is now required")
+			} else if beforeParam.Required && !afterParam.Required {
+				d.add(false, location, "parameter is now optional")
+			}
+			d.diffSchema(location, schemaValue(beforeParam.Schema), schemaValue(afterParam.Schema))
+		}
+	}
+}
+
+func (d *differ) diffRequestBody(before, after *openapi3.RequestBodyRef) {
+	beforeBody, afterBody := requestBodyValue(before), requestBodyValue(after)
+	switch {
+	case beforeBody == nil && afterBody == nil:
+		return
+	case afterBody == nil:
+		d.add(true, "request body", "request body removed")
+		return
+	case beforeBody == nil:
+		if afterBody.Required {
+			d.add(true, "request body", "required request body added")
+		} else {
+			d.add(false, "request body", "optional request body added")
+		}
+		return
+	}
+
+	if !beforeBody.Required && afterBody.Required {
+		d.add(true, "request body", "request body is now required")
+	} else if beforeBody.Required && !afterBody.Required {
+		d.add(false, "request body", "request body is now optional")
+	}
+
+	for _, mediaType := range sortedKeys(beforeBody.Content, afterBody.Content) {
+		location := fmt.Sprintf("request body (%s)", mediaType)
+		beforeMedia, inBefore := beforeBody.Content[mediaType]
+		afterMedia, inAfter := afterBody.Content[mediaType]
+		switch {
+		case !inAfter:
+			d.add(true, location, "media type removed")
+		case !inBefore:
+			d.add(false, location, "media type added")
+		default:
+			d.diffSchema(location, schemaValue(beforeMedia.Schema), schemaValue(afterMedia.Schema))
+		}
+	}
+}
+
+func (d *differ) diffSchema(location string, before, after *openapi3.Schema) {
+	if before == nil || after == nil {
+		return
+	}
+	pair := schemaPair{before: before, after: after}
+	if !d.enter(pair) {
+		return
+	}
+	defer d.leave(pair)
+
+	if before.Type != after.Type {
+		d.add(true, location, "type changed from %q to %q", before.Type, after.Type)
+		return
+	}
+	if before.Format != after.Format {
+		d.add(true, location, "format changed from %q to %q", before.Format, after.Format)
+	}
+	if before.Nullable && !after.Nullable {
+		d.add(true, location, "value is not nullable anymore")
+	} else if !before.Nullable && after.Nullable {
+		d.add(false, location, "value is now nullable")
+	}
+	switch {
+	case before.Pattern == after.Pattern:
+	case before.Pattern == "":
+		d.add(true, location, "pattern %q added", after.Pattern)
+	case after.Pattern == "":
+		d.add(false, location, "pattern removed")
+	default:
+		d.add(true, location, "pattern changed from %q to %q", before.Pattern, after.Pattern)
+	}
+
+	d.diffMaximum(location, "maximum", before.Max, after.Max)
+	d.diffMinimum(location, "minimum", before.Min, after.Min)
+	d.diffMaximum(location, "maximum length", uintToFloatPtr(before.MaxLength), uintToFloatPtr(after.MaxLength))
+	d.diffMinimum(location, "minimum length", minToFloatPtr(before.MinLength), minToFloatPtr(after.MinLength))
+	d.diffMaximum(location, "maximum items", uintToFloatPtr(before.MaxItems), uintToFloatPtr(after.MaxItems))
+	d.diffMinimum(location, "minimum items", minToFloatPtr(before.MinItems), minToFloatPtr(after.MinItems))
+	d.diffEnum(location, before.Enum, after.Enum)
+
+	if before.Items != nil || after.Items != nil {
+		d.diffSchema(location+" items", schemaValue(before.Items), schemaValue(after.Items))
+	}
+	d.diffProperties(location, before, after)
+}
+
+func (d *differ) diffResponses(before, after openapi3.Responses) {
+	for _, status := range sortedKeys(before, after) {
+		location := "response " + status
+		beforeResponse, inBefore := before[status]
+		afterResponse, inAfter := after[status]
+		switch {
+		case !inAfter:
+			d.add(true, location, "response removed")
+		case !inBefore:
+			d.add(false, location, "response added")
+		default:
+			d.diffResponseContent(location, responseValue(beforeResponse), responseValue(afterResponse))
+		}
+	}
+}
+
+func (d *differ) diffResponseContent(location string, before, after *openapi3.Response) {
+	if before == nil || after == nil {
+		return
+	}
+	for _, mediaType := range sortedKeys(before.Content, after.Content) {
+		mediaLocation := fmt.Sprintf("%s (%s)", location, mediaType)
+		beforeMedia, inBefore := before.Content[mediaType]
+		afterMedia, inAfter := after.Content[mediaType]
+		switch {
+		case !inAfter:
+			d.add(true, mediaLocation, "media type removed")
+		case !inBefore:
+			d.add(false, mediaLocation, "media type added")
+		default:
+			d.diffOutputSchema(mediaLocation, schemaValue(beforeMedia.Schema), schemaValue(afterMedia.Schema))
+		}
+	}
+}
+
+// diffOutputSchema compares the schemas of a response. Unlike inputs, adding a field
+// to a response is not breaking, but removing one is.
+func (d *differ) diffOutputSchema(location string, before, after *openapi3.Schema) {
+	if before == nil || after == nil {
+		return
+	}
+	pair := schemaPair{before: before, after: after, output: true}
+	if !d.enter(pair) {
+		return
+	}
+	defer d.leave(pair)
+	if before.Type != after.Type {
+		d.add(true, location, "type changed from %q to %q", before.Type, after.Type)
+		return
+	}
+	if before.Items != nil || after.Items != nil {
+		d.diffOutputSchema(location+" items", schemaValue(before.Items), schemaValue(after.Items))
+	}
+	beforeProps, afterProps := allProperties(before), allProperties(after)
+	for _, name := range sortedKeys(beforeProps, afterProps) {
+		fieldLocation := fmt.Sprintf("%s field %q", location, name)
+		beforeProp, inBefore := beforeProps[name]
+		afterProp, inAfter := afterProps[name]
+		switch {
+		case !inAfter:
+			d.add(true, fieldLocation, "field removed")
+		case !inBefore:
+			d.add(false, fieldLocation, "field added")
+		default:
+			d.diffOutputSchema(fieldLocation, schemaValue(beforeProp), schemaValue(afterProp))
+		}
+	}
+}
+
+func (d *differ) diffMaximum(location, name string, before, after *float64) {
+	switch {
+	case before == nil && after == nil:
+	case before == nil:
+		d.add(true, location, "%s set to %v", name, *after)
+	case after == nil:
+		d.add(false, location, "%s removed", name)
+	case *after < *before:
+		d.add(true, location, "%s lowered from %v to %v", name, *before, *after)
+	case *after > *before:
+		d.add(false, location, "%s raised from %v to %v", name, *before, *after)
+	}
+}
+
+func (d *differ) diffMinimum(location, name string, before, after *float64) {
+	switch {
+	case before == nil && after == nil:
+	case before == nil:
+		d.add(true, location, "%s set to %v", name, *after)
+	case after == nil:
+		d.add(false, location, "%s removed", name)
+	case *after > *before:
+		d.add(true, location, "%s raised from %v to %v", name, *before, *after)
+	case *after < *before:
+		d.add(false, location, "%s lowered from %v to %v", name, *before, *after)
+	}
+}
+
+func (d *differ) diffEnum(location string, before, after []interface{}) {
+	if len(after) == 0 {
+		if len(before) != 0 {
+			d.add(false, location, "enum removed")
+		}
+		return
+	}
+	if len(before) == 0 {
+		d.add(true, location, "enum added")
+		return
+	}
+	for _, v := range before {
+		if !containsValue(after, v) {
+			d.add(true, location, "enum value %v removed", v)
+		}
+	}
+	for _, v := range after {
+		if !containsValue(before, v) {
+			d.add(false, location, "enum value %v added", v)
+		}
+	}
+}
+
+func (d *differ) diffProperties(location string, before, after *openapi3.Schema) {
+	for _, name := range sortedKeys(before.Properties, after.Properties) {
+		fieldLocation := fmt.Sprintf("%s field %q", location, name)
+		beforeProp, inBefore := before.Properties[name]
+		afterProp, inAfter := after.Properties[name]
+		beforeRequired, afterRequired := containsString(before.Required, name), containsString(after.Required, name)
+		switch {
+		case !inAfter:
+			d.add(true, fieldLocation, "field removed")
+		case !inBefore:
+			if afterRequired {
+				d.add(true, fieldLocation, "required field added")
+			} else {
+				d.add(false, fieldLocation, "optional field added")
+			}
+		default:
+			if !beforeRequired && afterRequired {
+				d.add(true, fieldLocation, "field is now required")
+			} else if beforeRequired && !afterRequired {
+				d.add(false, fieldLocation, "field is now optional")
+			}
+			d.diffSchema(fieldLocation, schemaValue(beforeProp), schemaValue(afterProp))
+		}
+	}
+}
+
+// allProperties returns the properties of the given schema, including the
+// properties of the schemas it is composed of with "allOf".
+func allProperties(schema *openapi3.Schema) openapi3.Schemas {
+	if len(schema.AllOf) == 0 {
+		return schema.Properties
+	}
+	properties := openapi3.Schemas{}
+	for _, s := range schema.AllOf {
+		if s.Value != nil {
+			for name, p := range allProperties(s.Value) {
+				properties[name] = p
+			}
+		}
+	}
+	for name, p := range schema.Properties {
+		properties[name] = p
+	}
+	return properties
+}
+
+func schemaValue(ref *openapi3.SchemaRef) *openapi3.Schema {
+	if ref == nil {
+		return nil
+	}
+	return ref.Value
+}
+
+func responseValue(ref *openapi3.ResponseRef) *openapi3.Response {
+	if ref == nil {
+		return nil
+	}
+	return ref.Value
+}
+
+func requestBodyValue(ref *openapi3.RequestBodyRef) *openapi3.RequestBody {
+	if ref == nil {
+		return nil
+	}
+	return ref.Value
+}
+
+// minToFloatPtr returns nil if the given minimum is zero, meaning there is no minimum.
+func minToFloatPtr(u uint64) *float64 {
+	if u == 0 {
+		return nil
+	}
+	f := float64(u)
+	return &f
+}
+
+func uintToFloatPtr(u *uint64) *float64 {
+	if u == nil {
+		return nil
+	}
+	f := float64(*u)
+	return &f
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the union of the keys of the given maps, sorted alphabetically.
+func sortedKeys(maps ...interface{}) []string {
+	set := map[string]struct{}{}
+	for _, m := range maps {
+		for _, k := range reflect.ValueOf(m).MapKeys() {
+			set[k.String()] = struct{}{}
+		}
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,244 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+	"goyave.dev/goyave/v4/validation"
+)
+
+type DiffTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *DiffTestSuite) makeSpec(body *openapi3.Schema, params ...*openapi3.Parameter) *openapi3.T {
+	op := openapi3.NewOperation()
+	for _, p := range params {
+		op.AddParameter(p)
+	}
+	if body != nil {
+		op.RequestBody = &openapi3.RequestBodyRef{
+			Value: openapi3.NewRequestBody().WithRequired(true).WithJSONSchema(body),
+		}
+	}
+	pathItem := &openapi3.PathItem{}
+	pathItem.SetOperation("POST", op)
+	return &openapi3.T{
+		Paths: openapi3.Paths{"/users": pathItem},
+	}
+}
+
+func (suite *DiffTestSuite) diff(before, after *openapi3.T) *DiffReport {
+	report, err := Diff(before, after)
+	suite.Require().NoError(err)
+	return report
+}
+
+func (suite *DiffTestSuite) findChange(report *DiffReport, location, description string) *Change {
+	for _, c := range report.Changes {
+		if c.Location == location && c.Description == description {
+			return c
+		}
+	}
+	return nil
+}
+
+func (suite *DiffTestSuite) TestNoChanges() {
+	body := openapi3.NewObjectSchema().WithProperty("name", openapi3.NewStringSchema())
+	report := suite.diff(suite.makeSpec(body), suite.makeSpec(body))
+	suite.Empty(report.Changes)
+	suite.False(report.HasBreakingChanges())
+	suite.Equal("## API changes\n\nNo API changes.\n", report.Markdown())
+}
+
+func (suite *DiffTestSuite) TestOperations() {
+	before := suite.makeSpec(nil)
+	before.Paths["/removed"] = &openapi3.PathItem{Get: openapi3.NewOperation()}
+	after := suite.makeSpec(nil)
+	after.Paths["/users"].Get = openapi3.NewOperation()
+
+	report := suite.diff(before, after)
+	suite.Equal([]*Change{
+		{Operation: "GET /removed", Description: "operation removed", Breaking: true},
+		{Operation: "GET /users", Description: "operation added", Breaking: false},
+	}, report.Changes)
+	suite.True(report.HasBreakingChanges())
+}
+
+func (suite *DiffTestSuite) TestParameters() {
+	before := suite.makeSpec(nil,
+		openapi3.NewQueryParameter("page").WithSchema(openapi3.NewIntegerSchema()),
+		openapi3.NewQueryParameter("removed").WithSchema(openapi3.NewStringSchema()),
+		openapi3.NewQueryParameter("type").WithSchema(openapi3.NewIntegerSchema()),
+	)
+	after := suite.makeSpec(nil,
+		openapi3.NewQueryParameter("page").WithSchema(openapi3.NewIntegerSchema()).WithRequired(true),
+		openapi3.NewQueryParameter("type").WithSchema(openapi3.NewStringSchema()),
+		openapi3.NewQueryParameter("optional").WithSchema(openapi3.NewStringSchema()),
+		openapi3.NewQueryParameter("required").WithSchema(openapi3.NewStringSchema()).WithRequired(true),
+	)
+
+	report := suite.diff(before, after)
+	suite.Len(report.Changes, 5)
+	suite.True(suite.findChange(report, `query parameter "page"`, "parameter is now required").Breaking)
+	suite.True(suite.findChange(report, `query parameter "removed"`, "parameter removed").Breaking)
+	suite.True(suite.findChange(report, `query parameter "type"`, `type changed from "integer" to "string"`).Breaking)
+	suite.False(suite.findChange(report, `query parameter "optional"`, "optional parameter added").Breaking)
+	suite.True(suite.findChange(report, `query parameter "required"`, "required parameter added").Breaking)
+}
+
+func (suite *DiffTestSuite) TestRequestBody() {
+	before := openapi3.NewObjectSchema().
+		WithProperty("removed", openapi3.NewStringSchema()).
+		WithProperty("optional", openapi3.NewStringSchema()).
+		WithProperty("required", openapi3.NewStringSchema()).
+		WithProperty("max", openapi3.NewIntegerSchema().WithMax(10)).
+		WithProperty("min", openapi3.NewStringSchema().WithMinLength(2)).
+		WithProperty("enum", openapi3.NewStringSchema().WithEnum("a", "b")).
+		WithProperty("nullable", openapi3.NewStringSchema().WithNullable())
+	before.Required = []string{"required"}
+
+	after := openapi3.NewObjectSchema().
+		WithProperty("optional", openapi3.NewStringSchema()).
+		WithProperty("required", openapi3.NewStringSchema()).
+		WithProperty("max", openapi3.NewIntegerSchema().WithMax(5)).
+		WithProperty("min", openapi3.NewStringSchema().WithMinLength(1)).
+		WithProperty("enum", openapi3.NewStringSchema().WithEnum("a", "c")).
+		WithProperty("nullable", openapi3.NewStringSchema()).
+		WithProperty("added", openapi3.NewStringSchema())
+	after.Required = []string{"optional", "added"}
+
+	report := suite.diff(suite.makeSpec(before), suite.makeSpec(after))
+	suite.Len(report.Changes, 9)
+	suite.True(suite.findChange(report, `request body (application/json) field "removed"`, "field removed").Breaking)
+	suite.True(suite.findChange(report, `request body (application/json) field "optional"`, "field is now required").Breaking)
+	suite.False(suite.findChange(report, `request body (application/json) field "required"`, "field is now optional").Breaking)
+	suite.True(suite.findChange(report, `request body (application/json) field "max"`, "maximum lowered from 10 to 5").Breaking)
+	suite.False(suite.findChange(report, `request body (application/json) field "min"`, "minimum length lowered from 2 to 1").Breaking)
+	suite.True(suite.findChange(report, `request body (application/json) field "enum"`, "enum value b removed").Breaking)
+	suite.False(suite.findChange(report, `request body (application/json) field "enum"`, "enum value c added").Breaking)
+	suite.True(suite.findChange(report, `request body (application/json) field "nullable"`, "value is not nullable anymore").Breaking)
+	suite.True(suite.findChange(report, `request body (application/json) field "added"`, "required field added").Breaking)
+}
+
+func (suite *DiffTestSuite) TestRequestBodyAddedRemoved() {
+	body := openapi3.NewObjectSchema()
+	report := suite.diff(suite.makeSpec(nil), suite.makeSpec(body))
+	suite.Equal([]*Change{
+		{Operation: "POST /users", Location: "request body", Description: "required request body added", Breaking: true},
+	}, report.Changes)
+
+	report = suite.diff(suite.makeSpec(body), suite.makeSpec(nil))
+	suite.Equal([]*Change{
+		{Operation: "POST /users", Location: "request body", Description: "request body removed", Breaking: true},
+	}, report.Changes)
+}
+
+func (suite *DiffTestSuite) TestResponses() {
+	before := suite.makeSpec(nil)
+	before.Paths["/users"].Post.Responses = openapi3.Responses{
+		"200": &openapi3.ResponseRef{Value: openapi3.NewResponse().WithJSONSchema(
+			openapi3.NewObjectSchema().
+				WithProperty("id", openapi3.NewIntegerSchema()).
+				WithProperty("removed", openapi3.NewStringSchema()),
+		)},
+		"404": &openapi3.ResponseRef{Value: openapi3.NewResponse()},
+	}
+	after := suite.makeSpec(nil)
+	after.Paths["/users"].Post.Responses = openapi3.Responses{
+		"200": &openapi3.ResponseRef{Value: openapi3.NewResponse().WithJSONSchema(
+			openapi3.NewObjectSchema().
+				WithProperty("id", openapi3.NewStringSchema()).
+				WithProperty("added", openapi3.NewStringSchema()),
+		)},
+		"422": &openapi3.ResponseRef{Value: openapi3.NewResponse()},
+	}
+
+	report := suite.diff(before, after)
+	suite.Len(report.Changes, 5)
+	suite.False(suite.findChange(report, `response 200 (application/json) field "added"`, "field added").Breaking)
+	suite.True(suite.findChange(report, `response 200 (application/json) field "id"`, `type changed from "integer" to "string"`).Breaking)
+	suite.True(suite.findChange(report, `response 200 (application/json) field "removed"`, "field removed").Breaking)
+	suite.True(suite.findChange(report, "response 404", "response removed").Breaking)
+	suite.False(suite.findChange(report, "response 422", "response added").Breaking)
+}
+
+func (suite *DiffTestSuite) TestGenerated() {
+	generate := func(rules validation.RuleSet, queryRules validation.RuleSet) *openapi3.T {
+		router := goyave.NewRouter()
+		router.Post("/users", HandlerTest).Validate(rules)
+		router.Get("/users", HandlerTest).Validate(queryRules)
+		return NewGenerator().Generate(router)
+	}
+	before := generate(validation.RuleSet{
+		"name":    validation.List{"string"},
+		"age":     validation.List{"integer", "max:100"},
+		"removed": validation.List{"string"},
+	}, validation.RuleSet{
+		"page": validation.List{"integer"},
+	})
+	after := generate(validation.RuleSet{
+		"name": validation.List{"required", "string"},
+		"age":  validation.List{"integer", "max:50"},
+	}, validation.RuleSet{
+		"page": validation.List{"string"},
+	})
+
+	// Generated specs only contain refs to their components
+	suite.Require().Nil(before.Paths["/users"].Post.RequestBody.Value)
+
+	report := suite.diff(before, after)
+	suite.True(report.HasBreakingChanges())
+	suite.True(suite.findChange(report, `request body (application/json) field "removed"`, "field removed").Breaking)
+	suite.True(suite.findChange(report, `request body (application/json) field "name"`, "field is now required").Breaking)
+	suite.True(suite.findChange(report, `request body (application/json) field "age"`, "maximum lowered from 100 to 50").Breaking)
+	suite.True(suite.findChange(report, `query parameter "page"`, `type changed from "integer" to "string"`).Breaking)
+	suite.NotNil(suite.findChange(report, `response 422 (application/json) field "validationError" field "removed"`, "field removed"))
+
+	report = suite.diff(before, before)
+	suite.Empty(report.Changes)
+}
+
+func (suite *DiffTestSuite) TestUnresolvedRef() {
+	spec := suite.makeSpec(nil)
+	spec.Paths["/users"].Post.RequestBody = &openapi3.RequestBodyRef{Ref: "#/components/requestBodies/missing"}
+	_, err := Diff(spec, suite.makeSpec(nil))
+	suite.Error(err)
+}
+
+func (suite *DiffTestSuite) TestMarkdown() {
+	report := &DiffReport{Changes: []*Change{
+		{Operation: "GET /removed", Description: "operation removed", Breaking: true},
+		{Operation: "POST /users", Location: `query parameter "page"`, Description: "optional parameter added"},
+	}}
+	expected := "## API changes\n" +
+		"\n### Breaking changes\n\n" +
+		"- `GET /removed`: operation removed\n" +
+		"\n### Non-breaking changes\n\n" +
+		"- `POST /users`: query parameter \"page\": optional parameter added\n"
+	suite.Equal(expected, report.Markdown())
+}
+
+func (suite *DiffTestSuite) TestChangeString() {
+	suite.Equal("GET /removed: operation removed", (&Change{Operation: "GET /removed", Description: "operation removed"}).String())
+	suite.Equal(`POST /users: query parameter "page": parameter removed`, (&Change{Operation: "POST /users", Location: `query parameter "page"`, Description: "parameter removed"}).String())
+}
+
+func TestDiffSuite(t *testing.T) {
+	if err := config.LoadJSON(`{
+			"app": {
+				"name": "Generator"
+			},
+			"server": {
+				"protocol": "http",
+				"domain": "goyave.dev",
+				"port": 80
+			}
+		}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(DiffTestSuite))
+}
@@ -0,0 +1,536 @@
+package openapi3
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+	"goyave.dev/goyave/v4/validation"
+)
+
+// DiskCacheFile name of the file the generation cache is stored in, inside the
+// generator's CacheDir.
+const DiskCacheFile = "openapi3-cache.json"
+
+// diskCache generation cache persisted in the generator's CacheDir.
+type diskCache struct {
+	Fingerprint string          `json:"fingerprint"`
+	Spec        json.RawMessage `json:"spec,omitempty"`
+
+	// HandlerDocs indexed by handler fingerprint.
+	HandlerDocs map[string]*HandlerDoc `json:"handlerDocs"`
+
+	// Routes the parts of the spec generated from each route, indexed by route fingerprint.
+	Routes map[string]*cachedRoute `json:"routes,omitempty"`
+}
+
+// cachedRoute the part of the spec generated from a single route: its operations,
+// the parameters of its path, its tags and the components they reference.
+type cachedRoute struct {
+	Path       string                         `json:"path"`
+	Operations map[string]*openapi3.Operation `json:"operations"`
+	Parameters openapi3.Parameters            `json:"parameters,omitempty"`
+	Tags       openapi3.Tags                  `json:"tags,omitempty"`
+	Components *openapi3.Components           `json:"components"`
+}
+
+// routeFingerprint fingerprints of a route and of its handler.
+type routeFingerprint struct {
+	route       *goyave.Route
+	pc          uintptr
+	handler     string
+	fingerprint string
+}
+
+// loadDiskCache computes the fingerprint of the given router and returns the spec
+// stored in the cache directory if the fingerprints match. Otherwise, returns nil:
+// the documentation of the handlers whose source file didn't change is restored
+// in the cache so they are not analyzed again, and the routes whose fingerprint
+// didn't change are restored from the cache instead of being converted again.
+//
+// The spec and the routes are never reused if the generator has plugins.
+func (g *generation) loadDiskCache(router *goyave.Router) (*openapi3.T, error) {
+	routes, err := g.fingerprintRouter(router)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint, err := g.fingerprintGenerator(routes)
+	if err != nil {
+		return nil, err
+	}
+	g.routeFingerprints = make(map[*goyave.Route]*routeFingerprint, len(routes))
+	for _, r := range routes {
+		g.routeFingerprints[r.route] = r
+	}
+	g.fingerprint = fingerprint
+
+	disk := &diskCache{}
+	b, err := os.ReadFile(filepath.Join(g.CacheDir, DiskCacheFile))
+	if err != nil || json.Unmarshal(b, disk) != nil {
+		return nil, nil
+	}
+
+	reuse := len(g.plugins) == 0
+	if reuse && disk.Fingerprint == g.fingerprint && disk.Spec != nil {
+		// The refs are not resolved so the spec has the same shape as a generated one.
+		spec := &openapi3.T{}
+		if err := json.Unmarshal(disk.Spec, spec); err == nil {
+			return spec, nil
+		}
+	}
+
+	for _, r := range routes {
+		if doc, ok := disk.HandlerDocs[r.handler]; ok {
+			g.refs.setHandlerDoc(r.pc, doc)
+		}
+	}
+	if reuse {
+		g.cachedRoutes = disk.Routes
+	}
+	return nil, nil
+}
+
+// saveDiskCache writes the generated spec, the parts of the spec generated from each
+// route and the documentation of the handlers in the cache directory.
+func (g *generation) saveDiskCache() error {
+	disk := &diskCache{
+		Fingerprint: g.fingerprint,
+		HandlerDocs: make(map[string]*HandlerDoc, len(g.routeFingerprints)),
+		Routes:      g.generatedRoutes,
+	}
+	for _, r := range g.routeFingerprints {
+		if doc, ok := g.refs.getHandlerDoc(r.pc); ok {
+			disk.HandlerDocs[r.handler] = doc
+		}
+	}
+	spec, err := json.Marshal(g.spec)
+	if err != nil {
+		return err
+	}
+	disk.Spec = spec
+
+	b, err := json.Marshal(disk)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(g.CacheDir, 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a concurrent reader never sees a partial file.
+	tmp, err := os.CreateTemp(g.CacheDir, DiskCacheFile+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(g.CacheDir, DiskCacheFile)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("openapi3: could not write generation cache: %w", err)
+	}
+	return nil
+}
+
+// restoreRoute adds the part of the spec generated from the given route by a previous
+// generation to the spec, if the route didn't change. Returns false if the route must be
+// converted, for example if its operations or its operationIds conflict with the routes
+// converted before it.
+func (g *generation) restoreRoute(route *goyave.Route) bool {
+	r, ok := g.routeFingerprints[route]
+	if !ok {
+		return false
+	}
+	cached, ok := g.cachedRoutes[r.fingerprint]
+	if !ok || cached.Components == nil {
+		return false
+	}
+
+	pathItem := g.spec.Paths[cached.Path]
+	for method := range cached.Operations {
+		if pathItem != nil && pathItem.GetOperation(method) != nil {
+			return false
+		}
+	}
+	if !g.componentsCompatible(cached.Components) || !g.restoreOperationIDs(route, r.pc, cached) {
+		return false
+	}
+
+	for method, op := range cached.Operations {
+		g.spec.AddOperation(cached.Path, method, op)
+	}
+	if pathItem = g.spec.Paths[cached.Path]; pathItem != nil {
+		for _, p := range cached.Parameters {
+			if !containsParameterRef(pathItem.Parameters, p) {
+				pathItem.Parameters = append(pathItem.Parameters, p)
+			}
+		}
+	}
+	for _, tag := range cached.Tags {
+		if g.spec.Tags.Get(tag.Name) == nil {
+			g.spec.Tags = append(g.spec.Tags, tag)
+		}
+	}
+	g.restoreComponents(cached.Components)
+	return true
+}
+
+// restoreOperationIDs registers the operationIds of the cached operations of the given route.
+// Returns false if they are not the ones the route would get if it was converted, for
+// example because a route converted before this one now uses the same operationId.
+func (g *generation) restoreOperationIDs(route *goyave.Route, pc uintptr, cached *cachedRoute) bool {
+	funcName := runtime.FuncForPC(pc).Name()
+	registered := make([]string, 0, len(cached.Operations))
+	for _, method := range route.GetMethods() {
+		op, ok := cached.Operations[method]
+		if !ok {
+			continue
+		}
+		id := g.operationIDFunc()(route, method, funcName)
+		if id != "" {
+			id = g.uniqueOperationID(id)
+		}
+		if id != op.OperationID {
+			for _, id := range registered {
+				delete(g.operationIDs, id)
+			}
+			return false
+		}
+		if id != "" {
+			g.operationIDs[id] = struct{}{}
+			registered = append(registered, id)
+		}
+	}
+	return true
+}
+
+// componentsCompatible returns false if one of the given components already
+// exists in the spec with a different definition.
+func (g *generation) componentsCompatible(components *openapi3.Components) bool {
+	compatible := jsonEqual
+	for name, s := range components.Schemas {
+		if existing, ok := g.spec.Components.Schemas[name]; ok && !compatible(existing, s) {
+			return false
+		}
+	}
+	for name, p := range components.Parameters {
+		if existing, ok := g.spec.Components.Parameters[name]; ok && !compatible(existing, p) {
+			return false
+		}
+	}
+	for name, b := range components.RequestBodies {
+		if existing, ok := g.spec.Components.RequestBodies[name]; ok && !compatible(existing, b) {
+			return false
+		}
+	}
+	for name, r := range components.Responses {
+		if existing, ok := g.spec.Components.Responses[name]; ok && !compatible(existing, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonEqual returns true if the given values have the same JSON representation.
+func jsonEqual(a, b interface{}) bool {
+	jsonA, errA := json.Marshal(a)
+	jsonB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(jsonA, jsonB)
+}
+
+// restoreComponents adds the given components to the spec. The path parameters
+// and their schemas are registered in the refs so the routes converted after
+// this one reuse them.
+func (g *generation) restoreComponents(components *openapi3.Components) {
+	for name, s := range components.Schemas {
+		g.spec.Components.Schemas[name] = s
+		if strings.HasPrefix(name, "param") {
+			g.refs.ParamSchemas[name] = &openapi3.SchemaRef{Ref: "#/components/schemas/" + name}
+		}
+	}
+	for name, p := range components.Parameters {
+		g.spec.Components.Parameters[name] = p
+		if p.Value != nil && p.Value.In == openapi3.ParameterInPath {
+			g.refs.Parameters[name] = &openapi3.ParameterRef{Ref: "#/components/parameters/" + name}
+		}
+	}
+	for name, b := range components.RequestBodies {
+		g.spec.Components.RequestBodies[name] = b
+	}
+	for name, r := range components.Responses {
+		g.spec.Components.Responses[name] = r
+	}
+}
+
+// captureRoute stores the part of the spec generated from the given route so it can
+// be restored by the next generations. The given operations are the operations of the
+// route's path before it was converted. Nothing is stored if the generator has plugins.
+func (g *generation) captureRoute(route *goyave.Route, existing map[string]*openapi3.Operation) {
+	r, ok := g.routeFingerprints[route]
+	if !ok || len(g.plugins) != 0 {
+		return
+	}
+	cached := &cachedRoute{
+		Path:       cleanPath(route),
+		Operations: make(map[string]*openapi3.Operation),
+		Components: &openapi3.Components{
+			Schemas:       make(openapi3.Schemas),
+			Parameters:    make(openapi3.ParametersMap),
+			RequestBodies: make(openapi3.RequestBodies),
+			Responses:     make(openapi3.Responses),
+		},
+	}
+	pathItem := g.spec.Paths[cached.Path]
+	if pathItem == nil {
+		return
+	}
+	cached.Parameters = pathItem.Parameters
+	for method, op := range pathItem.Operations() {
+		if _, ok := existing[method]; ok {
+			continue
+		}
+		cached.Operations[method] = op
+		for _, name := range op.Tags {
+			if tag := g.spec.Tags.Get(name); tag != nil && cached.Tags.Get(name) == nil {
+				cached.Tags = append(cached.Tags, tag)
+			}
+		}
+	}
+	if err := g.captureComponents(cached.Components, cached.Operations, cached.Parameters); err != nil {
+		return
+	}
+	if g.generatedRoutes == nil {
+		g.generatedRoutes = make(map[string]*cachedRoute)
+	}
+	g.generatedRoutes[r.fingerprint] = cached
+}
+
+// captureComponents adds the components referenced by the given values to the
+// given components, recursively.
+func (g *generation) captureComponents(components *openapi3.Components, values ...interface{}) error {
+	for _, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		for _, match := range refFormat.FindAllSubmatch(b, -1) {
+			kind, name := string(match[1]), string(match[2])
+			var component interface{}
+			switch kind {
+			case "schemas":
+				if s, ok := g.spec.Components.Schemas[name]; ok && components.Schemas[name] == nil {
+					components.Schemas[name] = s
+					component = s
+				}
+			case "parameters":
+				if p, ok := g.spec.Components.Parameters[name]; ok && components.Parameters[name] == nil {
+					components.Parameters[name] = p
+					component = p
+				}
+			case "requestBodies":
+				if body, ok := g.spec.Components.RequestBodies[name]; ok && components.RequestBodies[name] == nil {
+					components.RequestBodies[name] = body
+					component = body
+				}
+			case "responses":
+				if r, ok := g.spec.Components.Responses[name]; ok && components.Responses[name] == nil {
+					components.Responses[name] = r
+					component = r
+				}
+			}
+			if component != nil {
+				if err := g.captureComponents(components, component); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func containsParameterRef(parameters openapi3.Parameters, ref *openapi3.ParameterRef) bool {
+	for _, p := range parameters {
+		if p.Ref != "" && p.Ref == ref.Ref {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprintGenerator returns the fingerprint of the generated spec: the fingerprints
+// of the given routes, the generator's settings and the config used by the generator.
+func (g *generation) fingerprintGenerator(routes []*routeFingerprint) (string, error) {
+	h := sha256.New()
+	settings := struct {
+		Version         string
+		AppName         string
+		BaseURL         string
+		TagDescriptions map[string]string
+		TagGroups       []*TagGroup
+		ExcludeStatic   bool
+		HeadAndOptions  bool
+		Security        []interface{}
+	}{
+		Version:         generatorVersion(),
+		AppName:         config.GetString("app.name"),
+		BaseURL:         goyave.BaseURL(),
+		TagDescriptions: g.TagDescriptions,
+		TagGroups:       g.TagGroups,
+		ExcludeStatic:   g.ExcludeStatic,
+		HeadAndOptions:  g.IncludeHeadAndOptions,
+	}
+	for _, s := range g.securitySchemes {
+		settings.Security = append(settings.Security, []interface{}{s.name, s.scopes, s.scheme})
+	}
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return "", fmt.Errorf("openapi3: could not fingerprint the generator settings: %w", err)
+	}
+	h.Write(b)
+	for _, r := range routes {
+		writeHashString(h, r.fingerprint)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fingerprintRouter returns the fingerprints of the routes of the given router and
+// its subrouters, in the order they are converted. The excluded routers are skipped.
+func (g *generation) fingerprintRouter(router *goyave.Router) ([]*routeFingerprint, error) {
+	files := make(map[string]string)
+	routes := make([]*routeFingerprint, 0, len(router.GetRoutes()))
+	if err := g.fingerprintRoutes(router, files, &routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+func (g *generation) fingerprintRoutes(router *goyave.Router, files map[string]string, routes *[]*routeFingerprint) error {
+	for _, excluded := range g.excluded {
+		if excluded == router {
+			return nil
+		}
+	}
+
+	for _, route := range router.GetRoutes() {
+		pc := reflect.ValueOf(route.GetHandler()).Pointer()
+		handlerValue := runtime.FuncForPC(pc)
+		file, _ := handlerValue.FileLine(pc)
+		fileHash, ok := files[file]
+		if !ok {
+			fileHash = hashFile(file)
+			files[file] = fileHash
+		}
+
+		h := sha256.New()
+		writeHashString(h, handlerValue.Name())
+		writeHashString(h, fileHash)
+		handler := hex.EncodeToString(h.Sum(nil))
+
+		// The package documentation is used as tag description.
+		packageDoc := ""
+		if file != "<autogenerated>" {
+			packageDoc = g.refs.packageDoc(file)
+		}
+
+		h = sha256.New()
+		writeHashString(h, handler)
+		writeHashString(h, route.GetFullURI())
+		writeHashString(h, route.GetName())
+		writeHashString(h, strings.Join(route.GetMethods(), ","))
+		writeRulesHash(h, route.GetValidationRules())
+		writeHashString(h, packageDoc)
+		options, err := json.Marshal(corsOptions(route.GetParent()))
+		if err != nil {
+			return fmt.Errorf("openapi3: could not fingerprint the CORS options of route %q: %w", route.GetFullURI(), err)
+		}
+		writeHashString(h, string(options))
+		for _, pc := range routeMiddleware(route) {
+			if fn := runtime.FuncForPC(pc); fn != nil {
+				writeHashString(h, fn.Name())
+			}
+			doc, err := json.Marshal(g.Middleware.get(pc))
+			if err != nil {
+				return fmt.Errorf("openapi3: could not fingerprint the middleware documentation of route %q: %w", route.GetFullURI(), err)
+			}
+			writeHashString(h, string(doc))
+		}
+
+		*routes = append(*routes, &routeFingerprint{
+			route:       route,
+			pc:          pc,
+			handler:     handler,
+			fingerprint: hex.EncodeToString(h.Sum(nil)),
+		})
+	}
+
+	for _, subrouter := range router.GetSubrouters() {
+		if err := g.fingerprintRoutes(subrouter, files, routes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hashFile returns the SHA-256 hash of the given file, or an empty string if
+// it cannot be read (for example if the sources are not available).
+func hashFile(file string) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeHashString writes the given string to the hash, followed by a separator
+// so consecutive strings cannot collide.
+func writeHashString(w io.Writer, s string) {
+	_, _ = io.WriteString(w, s)
+	_, _ = w.Write([]byte{0})
+}
+
+func writeRulesHash(w io.Writer, rules *validation.Rules) {
+	if rules == nil {
+		writeHashString(w, "")
+		return
+	}
+	for _, name := range sortedFieldNames(rules) {
+		writeHashString(w, name)
+		if field, ok := rules.Fields[name].(*validation.Field); ok {
+			writeFieldHash(w, field)
+		}
+	}
+}
+
+func writeFieldHash(w io.Writer, field *validation.Field) {
+	for _, r := range field.Rules {
+		writeHashString(w, r.Name)
+		writeHashString(w, strings.Join(r.Params, ","))
+	}
+	if field.Elements != nil {
+		writeHashString(w, "[]")
+		writeFieldHash(w, field.Elements)
+	}
+	writeHashString(w, "")
+}
@@ -0,0 +1,267 @@
+package openapi3
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+	"goyave.dev/goyave/v4/validation"
+)
+
+type countingPlugin struct {
+	BasePlugin
+	inits int
+}
+
+func (p *countingPlugin) Init(_ *openapi3.T) {
+	p.inits++
+}
+
+type DiskCacheTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *DiskCacheTestSuite) makeRouter(rules *validation.Rules) *goyave.Router {
+	router := goyave.NewRouter()
+	router.Get("/users", HandlerTest)
+	router.Post("/users", HandlerTest).Validate(rules)
+	return router
+}
+
+func (suite *DiskCacheTestSuite) makeRules(rule string) *validation.Rules {
+	return &validation.Rules{
+		Fields: validation.FieldMap{
+			"name": &validation.Field{Rules: []*validation.Rule{{Name: "required"}, {Name: rule}}},
+		},
+	}
+}
+
+func (suite *DiskCacheTestSuite) makeGenerator(dir string) *Generator {
+	generator := NewGenerator()
+	generator.CacheDir = dir
+	return generator
+}
+
+func (suite *DiskCacheTestSuite) readDiskCache(dir string) *diskCache {
+	b, err := os.ReadFile(filepath.Join(dir, DiskCacheFile))
+	suite.Require().NoError(err)
+	disk := &diskCache{}
+	suite.Require().NoError(json.Unmarshal(b, disk))
+	return disk
+}
+
+func (suite *DiskCacheTestSuite) writeDiskCache(dir string, disk *diskCache) {
+	b, err := json.Marshal(disk)
+	suite.Require().NoError(err)
+	suite.Require().NoError(os.WriteFile(filepath.Join(dir, DiskCacheFile), b, 0644))
+}
+
+func (suite *DiskCacheTestSuite) TestGenerateCached() {
+	dir := suite.T().TempDir()
+	router := suite.makeRouter(suite.makeRules("string"))
+
+	spec := suite.makeGenerator(dir).Generate(router)
+	suite.FileExists(filepath.Join(dir, DiskCacheFile))
+
+	cached := suite.makeGenerator(dir)
+	cachedSpec := cached.Generate(router)
+	suite.Empty(cached.Cache.Files)
+
+	expected, err := json.Marshal(spec)
+	suite.Require().NoError(err)
+	actual, err := json.Marshal(cachedSpec)
+	suite.Require().NoError(err)
+	suite.JSONEq(string(expected), string(actual))
+
+	// Same shape as a generated spec: refs are not resolved
+	suite.Nil(cachedSpec.Paths["/users"].Post.RequestBody.Value)
+	suite.Equal("#/components/requestBodies/HandlerTest", cachedSpec.Paths["/users"].Post.RequestBody.Ref)
+
+	// Base and patches are applied to the cached spec
+	cached.Patches = []Patch{JSONPatch{{Op: "replace", Path: "/info/version", Value: "1.0.0"}}}
+	suite.Equal("1.0.0", cached.Generate(router).Info.Version)
+}
+
+func (suite *DiskCacheTestSuite) TestGenerateCachedWithPlugins() {
+	dir := suite.T().TempDir()
+	router := suite.makeRouter(suite.makeRules("string"))
+
+	plugin := &countingPlugin{}
+	generator := suite.makeGenerator(dir)
+	generator.AddPlugin(plugin)
+	generator.Generate(router)
+	suite.Equal(1, plugin.inits)
+	suite.Empty(suite.readDiskCache(dir).Routes)
+
+	// Plugins can't be fingerprinted: the spec is generated again
+	generator.Generate(router)
+	suite.Equal(2, plugin.inits)
+}
+
+func (suite *DiskCacheTestSuite) TestGenerateRouterChanged() {
+	dir := suite.T().TempDir()
+	suite.makeGenerator(dir).Generate(suite.makeRouter(suite.makeRules("string")))
+
+	// Mark the cached operations to check which ones are reused
+	disk := suite.readDiskCache(dir)
+	suite.Len(disk.Routes, 2)
+	for _, route := range disk.Routes {
+		for _, op := range route.Operations {
+			op.Summary = "cached"
+		}
+	}
+	disk.Spec = nil
+	suite.writeDiskCache(dir, disk)
+
+	changed := suite.makeGenerator(dir)
+	spec := changed.Generate(suite.makeRouter(suite.makeRules("numeric")))
+
+	// Only the route whose rules changed is converted again
+	suite.Equal("cached", spec.Paths["/users"].Get.Summary)
+	suite.Equal("HandlerTest a test handler for AST reading", spec.Paths["/users"].Post.Summary)
+	suite.Equal("number", spec.Components.RequestBodies["HandlerTest"].Value.Content["application/json"].Schema.Value.Properties["name"].Value.Type)
+	suite.Equal("openapi3.HandlerTest", spec.Paths["/users"].Get.OperationID)
+	suite.Equal("openapi3.HandlerTest.2", spec.Paths["/users"].Post.OperationID)
+
+	// The source file didn't change: the handler documentation is restored from the cache
+	suite.Empty(changed.Cache.Files)
+	suite.Contains(changed.Cache.HandlerDocs, reflect.ValueOf(HandlerTest).Pointer())
+
+	// Settings are part of the fingerprint
+	settings := suite.makeGenerator(dir)
+	settings.TagDescriptions = map[string]string{"users": "Users"}
+	spec = settings.Generate(suite.makeRouter(suite.makeRules("numeric")))
+	suite.Equal("Users", spec.Tags.Get("users").Description)
+}
+
+func (suite *DiskCacheTestSuite) TestGenerateRestoredRouteConflict() {
+	dir := suite.T().TempDir()
+	router := goyave.NewRouter()
+	router.Get("/products", HandlerTest)
+	suite.makeGenerator(dir).Generate(router)
+
+	// A new route is converted before the cached one and takes its operationId
+	router = goyave.NewRouter()
+	router.Get("/users", HandlerTest)
+	router.Get("/products", HandlerTest)
+	spec := suite.makeGenerator(dir).Generate(router)
+	suite.Equal("openapi3.HandlerTest", spec.Paths["/users"].Get.OperationID)
+	suite.Equal("openapi3.HandlerTest.2", spec.Paths["/products"].Get.OperationID)
+}
+
+func (suite *DiskCacheTestSuite) TestGenerateInvalidCache() {
+	dir := suite.T().TempDir()
+	suite.Require().NoError(os.WriteFile(filepath.Join(dir, DiskCacheFile), []byte("{"), 0644))
+
+	spec := suite.makeGenerator(dir).Generate(suite.makeRouter(suite.makeRules("string")))
+	suite.NotNil(spec)
+
+	disk := suite.readDiskCache(dir)
+	suite.NotEmpty(disk.Fingerprint)
+	suite.Len(disk.HandlerDocs, 1)
+	suite.Len(disk.Routes, 2)
+}
+
+func (suite *DiskCacheTestSuite) TestGenerateUnwritableCache() {
+	file := filepath.Join(suite.T().TempDir(), "file")
+	suite.Require().NoError(os.WriteFile(file, []byte{}, 0644))
+
+	// The cache directory cannot be created inside a file
+	spec, err := suite.makeGenerator(filepath.Join(file, "cache")).GenerateE(suite.makeRouter(suite.makeRules("string")))
+	suite.Require().NoError(err)
+	suite.NotNil(spec)
+	suite.Contains(spec.Paths, "/users")
+}
+
+func (suite *DiskCacheTestSuite) TestFingerprintRouter() {
+	gen := &generation{Generator: NewGenerator(), refs: NewRefs()}
+	fingerprints := func(router *goyave.Router) []*routeFingerprint {
+		routes, err := gen.fingerprintRouter(router)
+		suite.Require().NoError(err)
+		return routes
+	}
+	router := suite.makeRouter(suite.makeRules("string"))
+	routes := fingerprints(router)
+	suite.Len(routes, 2)
+	suite.Equal(routes[0].handler, routes[1].handler)
+	suite.NotEqual(routes[0].fingerprint, routes[1].fingerprint)
+	suite.Equal(reflect.ValueOf(HandlerTest).Pointer(), routes[0].pc)
+	suite.Same(router.GetRoutes()[0], routes[0].route)
+
+	other := fingerprints(suite.makeRouter(suite.makeRules("string")))
+	suite.Equal(routes[0].fingerprint, other[0].fingerprint)
+	suite.Equal(routes[1].fingerprint, other[1].fingerprint)
+	suite.NotEqual(routes[1].fingerprint, fingerprints(suite.makeRouter(suite.makeRules("numeric")))[1].fingerprint)
+	fingerprint, err := gen.fingerprintGenerator(routes)
+	suite.Require().NoError(err)
+	suite.NotEmpty(fingerprint)
+
+	// The package documentation is used as tag description
+	pc := reflect.ValueOf(HandlerTest).Pointer()
+	file, _ := runtime.FuncForPC(pc).FileLine(pc)
+	gen.refs.setPackageDoc(filepath.Dir(file), "Edited package documentation.")
+	suite.NotEqual(routes[0].fingerprint, fingerprints(router)[0].fingerprint)
+
+	// The documentation of the middleware is fingerprinted
+	router = goyave.NewRouter()
+	router.Get("/users", HandlerTest).Middleware(requestIDMiddleware)
+	before := fingerprints(router)[0].fingerprint
+	gen.Middleware.Register(requestIDMiddleware, &MiddlewareDoc{
+		Parameters: openapi3.Parameters{{Value: openapi3.NewHeaderParameter("X-Request-Id")}},
+	})
+	suite.NotEqual(before, fingerprints(router)[0].fingerprint)
+
+	gen.Exclude(router)
+	suite.Empty(fingerprints(router))
+}
+
+func (suite *DiskCacheTestSuite) TestWriteRulesHash() {
+	hash := func(rules *validation.Rules) string {
+		h := sha256.New()
+		writeRulesHash(h, rules)
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	array := &validation.Rules{
+		Fields: validation.FieldMap{
+			"name": &validation.Field{
+				Rules:    []*validation.Rule{{Name: "array", Params: []string{"string"}}},
+				Elements: &validation.Field{Rules: []*validation.Rule{{Name: "max", Params: []string{"5"}}}},
+			},
+		},
+	}
+	suite.Equal(hash(suite.makeRules("string")), hash(suite.makeRules("string")))
+	suite.NotEqual(hash(suite.makeRules("string")), hash(suite.makeRules("numeric")))
+	suite.NotEqual(hash(nil), hash(suite.makeRules("string")))
+	suite.NotEqual(hash(array), hash(suite.makeRules("array")))
+}
+
+func (suite *DiskCacheTestSuite) TestHashFile() {
+	suite.Len(hashFile("diskcache.go"), 64)
+	suite.Empty(hashFile("notafile"))
+}
+
+func TestDiskCacheSuite(t *testing.T) {
+	if err := config.LoadJSON(`{
+			"app": {
+				"name": "Generator"
+			},
+			"server": {
+				"protocol": "http",
+				"domain": "goyave.dev",
+				"port": 80
+			}
+		}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(DiskCacheTestSuite))
+}
@@ -0,0 +1,142 @@
+package openapi3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+)
+
+const modulePath = "goyave.dev/openapi3"
+
+// Format serialization format of a spec.
+type Format string
+
+const (
+	// FormatJSON compact JSON.
+	FormatJSON Format = "json"
+
+	// FormatJSONIndent JSON indented with two spaces.
+	FormatJSONIndent Format = "json-indent"
+
+	// FormatYAML YAML.
+	FormatYAML Format = "yaml"
+)
+
+// EncodeOption option of Encode and WriteSpec.
+type EncodeOption func(*encodeOptions)
+
+type encodeOptions struct {
+	header bool
+}
+
+// WithHeader write the version of this generator and the version of the application
+// (the spec's "info.version") in the output. In YAML, they are written in a comment
+// at the top of the output. JSON doesn't support comments: the version of this
+// generator is written in the "x-generated-by" extension of the spec instead.
+func WithHeader() EncodeOption {
+	return func(o *encodeOptions) {
+		o.header = true
+	}
+}
+
+// Encode write the given spec to the given writer, using the given format.
+// The keys are sorted alphabetically so the output is stable.
+func Encode(spec *openapi3.T, w io.Writer, format Format, opts ...EncodeOption) error {
+	options := &encodeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.header && format != FormatYAML {
+		spec = withGeneratorExtension(spec)
+	}
+
+	var b []byte
+	var err error
+	switch format {
+	case FormatJSON:
+		b, err = json.Marshal(spec)
+	case FormatJSONIndent:
+		b, err = json.MarshalIndent(spec, "", "  ")
+	case FormatYAML:
+		b, err = json.Marshal(spec)
+		if err == nil {
+			b, err = yaml.JSONToYAML(b)
+		}
+	default:
+		return fmt.Errorf("openapi3: unknown format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if options.header && format == FormatYAML {
+		appVersion := ""
+		if spec.Info != nil {
+			appVersion = spec.Info.Version
+		}
+		fmt.Fprintf(buf, "# Code generated by %s. DO NOT EDIT.\n", generatedBy())
+		fmt.Fprintf(buf, "# App version: %s\n", appVersion)
+	}
+	buf.Write(b)
+	if !bytes.HasSuffix(b, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// WriteSpec write the given spec to the file at the given path, using the given format.
+// The file is created if it doesn't exist, and truncated otherwise.
+// See Encode for more details.
+func WriteSpec(spec *openapi3.T, path string, format Format, opts ...EncodeOption) error {
+	buf := &bytes.Buffer{}
+	if err := Encode(spec, buf, format, opts...); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// withGeneratorExtension returns a shallow copy of the given spec with the
+// "x-generated-by" extension. The given spec is not modified.
+func withGeneratorExtension(spec *openapi3.T) *openapi3.T {
+	copied := *spec
+	copied.Extensions = make(map[string]interface{}, len(spec.Extensions)+1)
+	for k, v := range spec.Extensions {
+		copied.Extensions[k] = v
+	}
+	copied.Extensions["x-generated-by"] = generatedBy()
+	return &copied
+}
+
+// generatedBy returns the module path and version of this generator.
+func generatedBy() string {
+	return modulePath + " " + generatorVersion()
+}
+
+// generatorVersion returns the version of this module used by the current binary,
+// or "(devel)" if it cannot be determined.
+func generatorVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == modulePath && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}
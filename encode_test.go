@@ -0,0 +1,94 @@
+package openapi3
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/suite"
+)
+
+type EncodeTestSuite struct {
+	suite.Suite
+}
+
+func (suite *EncodeTestSuite) makeSpec() *openapi3.T {
+	return &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "Test", Version: "1.2.3"},
+		Paths:   openapi3.Paths{},
+	}
+}
+
+func (suite *EncodeTestSuite) TestEncodeJSON() {
+	buf := &bytes.Buffer{}
+	suite.Require().NoError(Encode(suite.makeSpec(), buf, FormatJSON))
+	suite.Equal(`{"info":{"title":"Test","version":"1.2.3"},"openapi":"3.0.0","paths":{}}`+"\n", buf.String())
+}
+
+func (suite *EncodeTestSuite) TestEncodeJSONHeader() {
+	spec := suite.makeSpec()
+	spec.Extensions = map[string]interface{}{"x-logo": "logo.png"}
+	buf := &bytes.Buffer{}
+	suite.Require().NoError(Encode(spec, buf, FormatJSON, WithHeader()))
+	expected := `{"info":{"title":"Test","version":"1.2.3"},"openapi":"3.0.0","paths":{},"x-generated-by":"goyave.dev/openapi3 ` + generatorVersion() + `","x-logo":"logo.png"}` + "\n"
+	suite.Equal(expected, buf.String())
+	suite.Equal(map[string]interface{}{"x-logo": "logo.png"}, spec.Extensions) // Not modified
+}
+
+func (suite *EncodeTestSuite) TestEncodeJSONIndent() {
+	buf := &bytes.Buffer{}
+	suite.Require().NoError(Encode(suite.makeSpec(), buf, FormatJSONIndent))
+	expected := `{
+  "info": {
+    "title": "Test",
+    "version": "1.2.3"
+  },
+  "openapi": "3.0.0",
+  "paths": {}
+}
+`
+	suite.Equal(expected, buf.String())
+}
+
+func (suite *EncodeTestSuite) TestEncodeYAML() {
+	buf := &bytes.Buffer{}
+	suite.Require().NoError(Encode(suite.makeSpec(), buf, FormatYAML))
+	expected := `info:
+    title: Test
+    version: 1.2.3
+openapi: 3.0.0
+paths: {}
+`
+	suite.Equal(expected, buf.String())
+
+	buf.Reset()
+	suite.Require().NoError(Encode(suite.makeSpec(), buf, FormatYAML, WithHeader()))
+	suite.Equal("# Code generated by goyave.dev/openapi3 "+generatorVersion()+". DO NOT EDIT.\n# App version: 1.2.3\n"+expected, buf.String())
+}
+
+func (suite *EncodeTestSuite) TestEncodeUnknownFormat() {
+	buf := &bytes.Buffer{}
+	suite.Error(Encode(suite.makeSpec(), buf, Format("xml")))
+	suite.Empty(buf.String())
+}
+
+func (suite *EncodeTestSuite) TestWriteSpec() {
+	path := filepath.Join(suite.T().TempDir(), "openapi.yaml")
+	suite.Require().NoError(WriteSpec(suite.makeSpec(), path, FormatYAML))
+	b, err := os.ReadFile(path)
+	suite.Require().NoError(err)
+	suite.Contains(string(b), "openapi: 3.0.0\n")
+
+	suite.Error(WriteSpec(suite.makeSpec(), path, Format("xml")))
+}
+
+func (suite *EncodeTestSuite) TestGeneratorVersion() {
+	suite.NotEmpty(generatorVersion())
+}
+
+func TestEncodeSuite(t *testing.T) {
+	suite.Run(t, new(EncodeTestSuite))
+}
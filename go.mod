@@ -5,6 +5,7 @@ go 1.19
 require (
 	github.com/getkin/kin-openapi v0.118.0
 	github.com/imdario/mergo v0.3.16
+	github.com/invopop/yaml v0.1.0
 	github.com/stretchr/testify v1.8.4
 	golang.org/x/text v0.10.0
 	goyave.dev/goyave/v4 v4.4.11
@@ -16,7 +17,6 @@ require (
 	github.com/go-openapi/jsonpointer v0.19.5 // indirect
 	github.com/go-openapi/swag v0.21.1 // indirect
 	github.com/google/uuid v1.3.0 // indirect
-	github.com/invopop/yaml v0.1.0 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
@@ -0,0 +1,165 @@
+package openapi3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/invopop/yaml"
+	"goyave.dev/goyave/v4"
+)
+
+// UpdateSpecEnv name of the environment variable enabling the update mode of
+// Generator.AssertSpecFile. If this variable is set to "1" or "true", the spec
+// file is rewritten instead of being compared to the generated spec.
+const UpdateSpecEnv = "OPENAPI3_UPDATE_SPEC"
+
+// AssertSpecFile generate an OpenAPI 3 specification based on the given Router
+// and compare it to the spec file at the given path. The comparison is semantic:
+// formatting and keys order don't matter. If the specs are different, the test fails
+// with the list of the differences.
+//
+// If the UpdateSpecEnv environment variable is set, the spec file is rewritten with the
+// generated spec instead.
+//
+// Returns true if the generated spec matches the spec file.
+func (g *Generator) AssertSpecFile(t testing.TB, router *goyave.Router, path string) bool {
+	t.Helper()
+	spec, err := g.GenerateE(router)
+	if err != nil {
+		t.Errorf("openapi3: could not generate spec: %s", err)
+		return false
+	}
+
+	format := FormatJSONIndent
+	if formatFromPath(path) == "yaml" {
+		format = FormatYAML
+	}
+	buf := &bytes.Buffer{}
+	if err := Encode(spec, buf, format); err != nil {
+		t.Errorf("openapi3: could not marshal spec: %s", err)
+		return false
+	}
+	generated := buf.Bytes()
+
+	if isUpdateSpecMode() {
+		if err := os.WriteFile(path, generated, 0644); err != nil {
+			t.Errorf("openapi3: could not write spec file: %s", err)
+			return false
+		}
+		return true
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("openapi3: spec file %q doesn't exist. Run the tests with %s=1 to create it.", path, UpdateSpecEnv)
+		} else {
+			t.Errorf("openapi3: could not read spec file: %s", err)
+		}
+		return false
+	}
+
+	diff, err := diffSpecs(expected, generated)
+	if err != nil {
+		t.Errorf("openapi3: could not compare spec file %q: %s", path, err)
+		return false
+	}
+	if len(diff) != 0 {
+		t.Errorf("openapi3: the generated spec doesn't match the spec file %q:\n%s\nRun the tests with %s=1 to update it.", path, strings.Join(diff, "\n"), UpdateSpecEnv)
+		return false
+	}
+	return true
+}
+
+func isUpdateSpecMode() bool {
+	update, _ := strconv.ParseBool(os.Getenv(UpdateSpecEnv))
+	return update
+}
+
+// diffSpecs returns a readable list of the differences between the two given specs,
+// in JSON or YAML. Each line starts with "+" (added), "-" (removed) or "~" (changed),
+// followed by the JSON pointer of the value.
+func diffSpecs(expected, actual []byte) ([]string, error) {
+	var e, a interface{}
+	if err := yaml.Unmarshal(expected, &e); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(actual, &a); err != nil {
+		return nil, err
+	}
+	diff := []string{}
+	diffValues("", e, a, &diff)
+	return diff, nil
+}
+
+func diffValues(pointer string, expected, actual interface{}, diff *[]string) {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		if a, ok := actual.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(e)+len(a))
+			for k := range e {
+				keys = append(keys, k)
+			}
+			for k := range a {
+				if _, ok := e[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				p := pointer + "/" + escapePointer(k)
+				ev, inExpected := e[k]
+				av, inActual := a[k]
+				switch {
+				case !inActual:
+					*diff = append(*diff, fmt.Sprintf("- %s: %s", p, formatValue(ev)))
+				case !inExpected:
+					*diff = append(*diff, fmt.Sprintf("+ %s: %s", p, formatValue(av)))
+				default:
+					diffValues(p, ev, av, diff)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if a, ok := actual.([]interface{}); ok {
+			for i := 0; i < len(e) || i < len(a); i++ {
+				p := pointer + "/" + strconv.Itoa(i)
+				switch {
+				case i >= len(a):
+					*diff = append(*diff, fmt.Sprintf("- %s: %s", p, formatValue(e[i])))
+				case i >= len(e):
+					*diff = append(*diff, fmt.Sprintf("+ %s: %s", p, formatValue(a[i])))
+				default:
+					diffValues(p, e[i], a[i], diff)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		*diff = append(*diff, fmt.Sprintf("~ %s: %s => %s", pointer, formatValue(expected), formatValue(actual)))
+	}
+}
+
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func formatValue(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(b)
+}
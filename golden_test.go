@@ -0,0 +1,123 @@
+package openapi3
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+)
+
+type testingTBMock struct {
+	testing.TB
+	errors []string
+}
+
+func (t *testingTBMock) Helper() {}
+
+func (t *testingTBMock) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+type GoldenTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *GoldenTestSuite) makeRouter() *goyave.Router {
+	router := goyave.NewRouter()
+	router.Get("/users", HandlerTest)
+	return router
+}
+
+func (suite *GoldenTestSuite) TestAssertSpecFile() {
+	path := filepath.Join(suite.T().TempDir(), "openapi.json")
+
+	t := &testingTBMock{}
+	suite.False(NewGenerator().AssertSpecFile(t, suite.makeRouter(), path))
+	suite.Len(t.errors, 1)
+	suite.Contains(t.errors[0], "doesn't exist")
+
+	suite.T().Setenv(UpdateSpecEnv, "1")
+	t = &testingTBMock{}
+	suite.True(NewGenerator().AssertSpecFile(t, suite.makeRouter(), path))
+	suite.Empty(t.errors)
+	suite.FileExists(path)
+
+	suite.T().Setenv(UpdateSpecEnv, "")
+	t = &testingTBMock{}
+	suite.True(NewGenerator().AssertSpecFile(t, suite.makeRouter(), path))
+	suite.Empty(t.errors)
+
+	router := suite.makeRouter()
+	router.Post("/users", HandlerTest)
+	t = &testingTBMock{}
+	suite.False(NewGenerator().AssertSpecFile(t, router, path))
+	suite.Len(t.errors, 1)
+	suite.Contains(t.errors[0], "+ /paths/~1users/post: ")
+}
+
+func (suite *GoldenTestSuite) TestAssertSpecFileSemantic() {
+	path := filepath.Join(suite.T().TempDir(), "openapi.json")
+	spec := NewGenerator().Generate(suite.makeRouter())
+	b, err := spec.MarshalJSON() // Compact, different from the indented generated spec
+	suite.Require().NoError(err)
+	suite.Require().NoError(os.WriteFile(path, b, 0644))
+
+	t := &testingTBMock{}
+	suite.True(NewGenerator().AssertSpecFile(t, suite.makeRouter(), path))
+	suite.Empty(t.errors)
+}
+
+func (suite *GoldenTestSuite) TestAssertSpecFileYAML() {
+	path := filepath.Join(suite.T().TempDir(), "openapi.yaml")
+	suite.Require().NoError(WriteSpec(NewGenerator().Generate(suite.makeRouter()), path, FormatYAML, WithHeader()))
+
+	t := &testingTBMock{}
+	suite.True(NewGenerator().AssertSpecFile(t, suite.makeRouter(), path))
+	suite.Empty(t.errors)
+}
+
+func (suite *GoldenTestSuite) TestDiffSpecsYAML() {
+	diff, err := diffSpecs([]byte("a:\n  b: 1\n"), []byte(`{"a":{"b":2}}`))
+	suite.Require().NoError(err)
+	suite.Equal([]string{"~ /a/b: 1 => 2"}, diff)
+}
+
+func (suite *GoldenTestSuite) TestDiffSpecs() {
+	expected := []byte(`{"a":{"b":1,"c/d":[1,2]},"removed":true,"type":"string"}`)
+	actual := []byte(`{"a":{"b":2,"c/d":[1]},"added":"value","type":"string"}`)
+	diff, err := diffSpecs(expected, actual)
+	suite.Require().NoError(err)
+	suite.Equal([]string{
+		"~ /a/b: 1 => 2",
+		"- /a/c~1d/1: 2",
+		"+ /added: \"value\"",
+		"- /removed: true",
+	}, diff)
+
+	diff, err = diffSpecs(expected, expected)
+	suite.Require().NoError(err)
+	suite.Empty(diff)
+
+	_, err = diffSpecs([]byte("{"), actual)
+	suite.Error(err)
+}
+
+func TestGoldenSuite(t *testing.T) {
+	if err := config.LoadJSON(`{
+			"app": {
+				"name": "Generator"
+			},
+			"server": {
+				"protocol": "http",
+				"domain": "goyave.dev",
+				"port": 80
+			}
+		}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(GoldenTestSuite))
+}
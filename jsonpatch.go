@@ -0,0 +1,310 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+)
+
+// JSONPatch RFC 6902 JSON Patch document: a list of operations applied in order.
+type JSONPatch []*JSONPatchOperation
+
+// JSONPatchOperation a single operation of a JSONPatch.
+// The supported operations are "add", "remove", "replace", "move", "copy" and "test".
+//
+// A nil Value is the JSON null value. When a patch is decoded, the "value" member
+// is required by the "add", "replace" and "test" operations.
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON encodes the operation. The "value" member is only written for the
+// operations that take a value, so a null value is not lost.
+func (op *JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	type operation JSONPatchOperation
+	if hasPatchValue(op.Op) {
+		return json.Marshal((*operation)(op))
+	}
+	return json.Marshal(struct {
+		Op   string `json:"op"`
+		Path string `json:"path"`
+		From string `json:"from,omitempty"`
+	}{op.Op, op.Path, op.From})
+}
+
+// UnmarshalJSON decodes the operation and checks the "value" member is present
+// if the operation takes a value, because a missing value cannot be told apart
+// from a null value once decoded.
+func (op *JSONPatchOperation) UnmarshalJSON(b []byte) error {
+	type operation JSONPatchOperation
+	if err := json.Unmarshal(b, (*operation)(op)); err != nil {
+		return err
+	}
+	if !hasPatchValue(op.Op) {
+		return nil
+	}
+	members := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &members); err != nil {
+		return err
+	}
+	if _, ok := members["value"]; !ok {
+		return fmt.Errorf("openapi3: JSON patch operation %s %q: missing value", op.Op, op.Path)
+	}
+	return nil
+}
+
+// hasPatchValue returns true if the given JSON patch operation takes a value.
+func hasPatchValue(op string) bool {
+	return op == "add" || op == "replace" || op == "test"
+}
+
+// LoadJSONPatch load the JSON Patch document (JSON or YAML) at the given path.
+func LoadJSONPatch(path string) (JSONPatch, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	patch := JSONPatch{}
+	if err := yaml.Unmarshal(b, &patch); err != nil {
+		return nil, fmt.Errorf("openapi3: could not parse JSON patch %q: %w", path, err)
+	}
+	return patch, nil
+}
+
+// Apply the operations of the patch to the given spec, in order. If an operation
+// fails, an error is returned and none of the operations are applied.
+// The given spec is not modified.
+func (p JSONPatch) Apply(spec *openapi3.T) (*openapi3.T, error) {
+	doc, err := toTree(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, op := range p {
+		doc, err = op.apply(doc)
+		if err != nil {
+			return nil, fmt.Errorf("openapi3: JSON patch operation %d (%s %q): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return fromTree(doc)
+}
+
+func (op *JSONPatchOperation) apply(doc interface{}) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// The document only contains JSON types (float64 numbers, etc), but the
+	// value of the operation can be any Go value
+	var value interface{}
+	if hasPatchValue(op.Op) {
+		if value, err = normalizeTree(op.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		return addValue(doc, path, value)
+	case "remove":
+		return removeValue(doc, path)
+	case "replace":
+		if _, err := getValue(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		return modifyParent(doc, path, func(parent interface{}, token string) (interface{}, error) {
+			return setChild(parent, token, value)
+		})
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err = getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if len(path) > len(from) && reflect.DeepEqual(path[:len(from)], from) {
+				return nil, fmt.Errorf("cannot move a value into one of its children")
+			}
+			if doc, err = removeValue(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			value = cloneTree(value)
+		}
+		return addValue(doc, path, value)
+	case "test":
+		actual, err := getValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(actual, value) {
+			return nil, fmt.Errorf("test failed")
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation")
+	}
+}
+
+// parsePointer parses the given RFC 6901 JSON pointer into reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func getValue(doc interface{}, path []string) (interface{}, error) {
+	value := doc
+	for _, token := range path {
+		child, err := getChild(value, token)
+		if err != nil {
+			return nil, err
+		}
+		value = child
+	}
+	return value, nil
+}
+
+func getChild(value interface{}, token string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		child, ok := v[token]
+		if !ok {
+			return nil, fmt.Errorf("member %q not found", token)
+		}
+		return child, nil
+	case []interface{}:
+		i, err := arrayIndex(token, len(v)-1)
+		if err != nil {
+			return nil, err
+		}
+		return v[i], nil
+	default:
+		return nil, fmt.Errorf("cannot get %q: not an object or an array", token)
+	}
+}
+
+func setChild(parent interface{}, token string, value interface{}) (interface{}, error) {
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[token] = value
+		return p, nil
+	case []interface{}:
+		i, err := arrayIndex(token, len(p)-1)
+		if err != nil {
+			return nil, err
+		}
+		p[i] = value
+		return p, nil
+	default:
+		return nil, fmt.Errorf("cannot set %q: not an object or an array", token)
+	}
+}
+
+// arrayIndex parses the given array index token and checks it is not greater than max.
+func arrayIndex(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > max {
+		return 0, fmt.Errorf("array index %d out of bounds", i)
+	}
+	return i, nil
+}
+
+// modifyParent calls the given function with the parent of the value at the given path
+// and the last token of the path. The function returns the new parent, which replaces the
+// old one in the document. Returns the new document.
+func modifyParent(doc interface{}, path []string, modify func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return modify(doc, path[0])
+	}
+	child, err := getChild(doc, path[0])
+	if err != nil {
+		return nil, err
+	}
+	newChild, err := modifyParent(child, path[1:], modify)
+	if err != nil {
+		return nil, err
+	}
+	return setChild(doc, path[0], newChild)
+}
+
+func addValue(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return modifyParent(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[token] = value
+			return p, nil
+		case []interface{}:
+			i := len(p)
+			if token != "-" {
+				var err error
+				if i, err = arrayIndex(token, len(p)); err != nil {
+					return nil, err
+				}
+			}
+			a := make([]interface{}, 0, len(p)+1)
+			a = append(a, p[:i]...)
+			a = append(a, value)
+			return append(a, p[i:]...), nil
+		default:
+			return nil, fmt.Errorf("cannot add %q: not an object or an array", token)
+		}
+	})
+}
+
+func removeValue(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("cannot remove the root node")
+	}
+	return modifyParent(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := p[token]; !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			delete(p, token)
+			return p, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(p)-1)
+			if err != nil {
+				return nil, err
+			}
+			a := make([]interface{}, 0, len(p)-1)
+			a = append(a, p[:i]...)
+			return append(a, p[i+1:]...), nil
+		default:
+			return nil, fmt.Errorf("cannot remove %q: not an object or an array", token)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type JSONPatchTestSuite struct {
+	suite.Suite
+}
+
+func (suite *JSONPatchTestSuite) TestApply() {
+	patch := JSONPatch{
+		{Op: "remove", Path: "/paths/~1admin~1users"},
+		{Op: "add", Path: "/servers/-", Value: map[string]interface{}{"url": "http://last"}},
+		{Op: "add", Path: "/servers/0", Value: map[string]interface{}{"url": "http://first"}},
+		{Op: "replace", Path: "/info/title", Value: "Patched"},
+		{Op: "copy", From: "/info/title", Path: "/info/description"},
+		{Op: "move", From: "/info/version", Path: "/info/x-version"},
+		{Op: "add", Path: "/info/version", Value: "2.0.0"},
+		{Op: "test", Path: "/info/title", Value: "Patched"},
+	}
+
+	original := makePatchTestSpec()
+	spec, err := patch.Apply(original)
+	suite.Require().NoError(err)
+
+	suite.NotContains(spec.Paths, "/admin/users")
+	suite.Len(spec.Servers, 4)
+	suite.Equal("http://first", spec.Servers[0].URL)
+	suite.Equal("http://localhost", spec.Servers[1].URL)
+	suite.Equal("http://last", spec.Servers[3].URL)
+	suite.Equal("Patched", spec.Info.Title)
+	suite.Equal("Patched", spec.Info.Description)
+	suite.Equal("2.0.0", spec.Info.Version)
+	suite.Equal("1.0.0", spec.Info.Extensions["x-version"])
+	// Refs are not resolved, like in a generated spec
+	suite.Equal("#/components/requestBodies/user", spec.Paths["/users"].Post.RequestBody.Ref)
+	suite.Nil(spec.Paths["/users"].Post.RequestBody.Value)
+
+	// The original spec is not modified
+	suite.Contains(original.Paths, "/admin/users")
+	suite.Len(original.Servers, 2)
+}
+
+func (suite *JSONPatchTestSuite) TestApplyNullAndNumbers() {
+	patch := JSONPatch{
+		{Op: "add", Path: "/info/x-null", Value: nil},
+		{Op: "test", Path: "/info/x-null", Value: nil},
+		{Op: "add", Path: "/info/x-count", Value: 3},
+		{Op: "test", Path: "/info/x-count", Value: 3},
+		{Op: "test", Path: "/info/x-count", Value: 3.0},
+		{Op: "test", Path: "/servers/0", Value: map[string]string{"url": "http://localhost"}},
+	}
+
+	spec, err := patch.Apply(makePatchTestSpec())
+	suite.Require().NoError(err)
+	suite.Contains(spec.Info.Extensions, "x-null")
+	suite.Nil(spec.Info.Extensions["x-null"])
+
+	_, err = JSONPatch{{Op: "test", Path: "/info/x-unknown", Value: nil}}.Apply(makePatchTestSpec())
+	suite.Error(err)
+	_, err = JSONPatch{{Op: "test", Path: "/info/title", Value: 3}}.Apply(makePatchTestSpec())
+	suite.Error(err)
+}
+
+func (suite *JSONPatchTestSuite) TestMarshalJSON() {
+	patch := JSONPatch{
+		{Op: "remove", Path: "/info/x-env"},
+		{Op: "add", Path: "/info/x-null", Value: nil},
+		{Op: "move", From: "/info/title", Path: "/info/x-title"},
+	}
+	b, err := json.Marshal(patch)
+	suite.Require().NoError(err)
+	suite.Equal(`[{"op":"remove","path":"/info/x-env"},{"op":"add","path":"/info/x-null","value":null},{"op":"move","path":"/info/x-title","from":"/info/title"}]`, string(b))
+
+	decoded := JSONPatch{}
+	suite.Require().NoError(json.Unmarshal(b, &decoded))
+	suite.Equal(patch, decoded)
+}
+
+func (suite *JSONPatchTestSuite) TestApplyErrors() {
+	patches := []JSONPatch{
+		{{Op: "test", Path: "/info/title", Value: "Other"}},
+		{{Op: "remove", Path: "/unknown"}},
+		{{Op: "remove", Path: ""}},
+		{{Op: "replace", Path: "/unknown", Value: 1}},
+		{{Op: "add", Path: "/servers/5", Value: 1}},
+		{{Op: "add", Path: "/servers/01", Value: 1}},
+		{{Op: "add", Path: "info", Value: 1}},
+		{{Op: "add", Path: "/openapi/a", Value: 1}},
+		{{Op: "move", From: "/info", Path: "/info/x"}},
+		{{Op: "copy", From: "/unknown", Path: "/info/x"}},
+		{{Op: "unknown", Path: "/info"}},
+	}
+	for _, patch := range patches {
+		_, err := patch.Apply(makePatchTestSpec())
+		suite.Error(err, patch[0])
+	}
+}
+
+func (suite *JSONPatchTestSuite) TestParsePointer() {
+	tokens, err := parsePointer("")
+	suite.Require().NoError(err)
+	suite.Empty(tokens)
+
+	tokens, err = parsePointer("/paths/~1users~1{id}/get/x~0y")
+	suite.Require().NoError(err)
+	suite.Equal([]string{"paths", "/users/{id}", "get", "x~y"}, tokens)
+
+	_, err = parsePointer("paths")
+	suite.Error(err)
+}
+
+func (suite *JSONPatchTestSuite) TestLoadJSONPatch() {
+	path := filepath.Join(suite.T().TempDir(), "patch.json")
+	content := `[{"op": "remove", "path": "/paths/~1admin~1users"}, {"op": "add", "path": "/info/x-env", "value": "production"}]`
+	suite.Require().NoError(os.WriteFile(path, []byte(content), 0644))
+	patch, err := LoadJSONPatch(path)
+	suite.Require().NoError(err)
+	suite.Equal(JSONPatch{
+		{Op: "remove", Path: "/paths/~1admin~1users"},
+		{Op: "add", Path: "/info/x-env", Value: "production"},
+	}, patch)
+
+	_, err = LoadJSONPatch(filepath.Join(suite.T().TempDir(), "notafile.json"))
+	suite.Error(err)
+
+	// The value is required, null is a value
+	suite.Require().NoError(os.WriteFile(path, []byte(`[{"op": "add", "path": "/info/x-env", "value": null}]`), 0644))
+	patch, err = LoadJSONPatch(path)
+	suite.Require().NoError(err)
+	suite.Equal(JSONPatch{{Op: "add", Path: "/info/x-env"}}, patch)
+
+	suite.Require().NoError(os.WriteFile(path, []byte(`[{"op": "add", "path": "/info/x-env"}]`), 0644))
+	_, err = LoadJSONPatch(path)
+	suite.Error(err)
+}
+
+func TestJSONPatchSuite(t *testing.T) {
+	suite.Run(t, new(JSONPatchTestSuite))
+}
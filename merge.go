@@ -0,0 +1,388 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// MergeStrategy defines how conflicts are resolved when merging a generated spec
+// into a base document.
+type MergeStrategy int
+
+const (
+	// MergeBaseWins the definitions of the base document override the generated ones.
+	MergeBaseWins MergeStrategy = iota
+
+	// MergeGeneratedWins the generated definitions override the ones of the base document.
+	MergeGeneratedWins
+
+	// MergeError an error is returned if the base document and the generated spec both
+	// define the same component or operation differently. Only the fields defined in the
+	// base document are compared: a base operation can extend the generated operation,
+	// for example by defining additional responses.
+	MergeError
+)
+
+// LoadSpec load the OpenAPI 3 document (JSON or YAML) at the given path.
+// The refs are resolved.
+func LoadSpec(path string) (*openapi3.T, error) {
+	return openapi3.NewLoader().LoadFromFile(path)
+}
+
+// Merge deep-merge the paths and components of the generated spec into the given base
+// document, and returns the result. Neither of the given specs is modified.
+//
+// The top-level fields of the base document (info, servers, security, etc) are kept.
+// If they are not defined, the ones of the generated spec are used. Tags are merged by name.
+//
+// Components are merged by name. Paths are merged at the operation level: if both specs define
+// the same operation, the fields of the operation that wins override the other's.
+// The fields that are not defined are taken from the other operation. Parameters are merged
+// by location and name, and responses by status code. For example, a base document
+// can only define the responses of an operation and let the generator handle the rest.
+//
+// Conflicts are resolved using the given MergeStrategy.
+func Merge(base, generated *openapi3.T, strategy MergeStrategy) (*openapi3.T, error) {
+	m := &merger{strategy: strategy}
+	spec := &openapi3.T{
+		Extensions:   mergeMaps(base.Extensions, generated.Extensions).(map[string]interface{}),
+		OpenAPI:      base.OpenAPI,
+		Info:         base.Info,
+		Security:     base.Security,
+		Servers:      base.Servers,
+		Tags:         mergeTags(base.Tags, generated.Tags),
+		ExternalDocs: base.ExternalDocs,
+	}
+	if spec.OpenAPI == "" {
+		spec.OpenAPI = generated.OpenAPI
+	}
+	if spec.Info == nil {
+		spec.Info = generated.Info
+	}
+	if len(spec.Security) == 0 {
+		spec.Security = generated.Security
+	}
+	if len(spec.Servers) == 0 {
+		spec.Servers = generated.Servers
+	}
+	if spec.ExternalDocs == nil {
+		spec.ExternalDocs = generated.ExternalDocs
+	}
+
+	components, err := m.mergeComponents(base.Components, generated.Components)
+	if err != nil {
+		return nil, err
+	}
+	spec.Components = components
+
+	paths, err := m.mergePaths(base.Paths, generated.Paths)
+	if err != nil {
+		return nil, err
+	}
+	spec.Paths = paths
+	return spec, nil
+}
+
+type merger struct {
+	strategy MergeStrategy
+}
+
+// resolve returns the definition that wins the conflict between the given definitions.
+// The values must be pointers. The second returned value is the one that loses.
+func (m *merger) resolve(kind, name string, base, generated interface{}) (interface{}, interface{}, error) {
+	switch m.strategy {
+	case MergeGeneratedWins:
+		return generated, base, nil
+	case MergeError:
+		conflict, err := conflicting(base, generated)
+		if err != nil {
+			return nil, nil, err
+		}
+		if conflict {
+			return nil, nil, fmt.Errorf("openapi3: conflicting definitions of %s %q in base document and generated spec", kind, name)
+		}
+	}
+	return base, generated, nil
+}
+
+// conflicting returns true if one of the fields defined in the base definition has
+// a different value in the generated definition. The definitions are compared using
+// their JSON representation so refs are compared by name, whether they are resolved
+// or not.
+func conflicting(base, generated interface{}) (bool, error) {
+	var b, g interface{}
+	if err := remarshal(base, &b); err != nil {
+		return false, err
+	}
+	if err := remarshal(generated, &g); err != nil {
+		return false, err
+	}
+	return jsonConflict(b, g), nil
+}
+
+func remarshal(value interface{}, result *interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+// jsonConflict returns true if the base JSON value is not a subset of the generated one.
+// Parameters lists are compared by location and name.
+func jsonConflict(base, generated interface{}) bool {
+	switch b := base.(type) {
+	case map[string]interface{}:
+		g, ok := generated.(map[string]interface{})
+		if !ok {
+			return true
+		}
+		for key, value := range b {
+			if generatedValue, exists := g[key]; exists && jsonConflict(value, generatedValue) {
+				return true
+			}
+		}
+		return false
+	case []interface{}:
+		g, ok := generated.([]interface{})
+		if !ok {
+			return true
+		}
+		baseParams, isParams := indexParameters(b)
+		generatedParams, isGeneratedParams := indexParameters(g)
+		if !isParams || !isGeneratedParams {
+			return !reflect.DeepEqual(b, g)
+		}
+		for key, p := range baseParams {
+			if generatedParam, exists := generatedParams[key]; exists && jsonConflict(p, generatedParam) {
+				return true
+			}
+		}
+		return false
+	default:
+		return !reflect.DeepEqual(base, generated)
+	}
+}
+
+// indexParameters indexes the given JSON parameters by location and name, or by ref.
+// Returns false if one of the values is not a parameter.
+func indexParameters(values []interface{}) (map[string]interface{}, bool) {
+	params := make(map[string]interface{}, len(values))
+	for _, v := range values {
+		p, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if ref, ok := p["$ref"].(string); ok {
+			params["$ref "+ref] = p
+			continue
+		}
+		in, okIn := p["in"].(string)
+		name, okName := p["name"].(string)
+		if !okIn || !okName {
+			return nil, false
+		}
+		params[in+" "+name] = p
+	}
+	return params, true
+}
+
+func (m *merger) mergeComponents(base, generated *openapi3.Components) (*openapi3.Components, error) {
+	if base == nil {
+		return generated, nil
+	}
+	if generated == nil {
+		return base, nil
+	}
+
+	result := &openapi3.Components{}
+	b, g, r := reflect.ValueOf(base).Elem(), reflect.ValueOf(generated).Elem(), reflect.ValueOf(result).Elem()
+	for i := 0; i < r.NumField(); i++ {
+		kind := strings.Split(r.Type().Field(i).Tag.Get("json"), ",")[0]
+		if kind == "-" {
+			kind = "extensions"
+		}
+		merged := reflect.MakeMap(r.Field(i).Type())
+		iter := b.Field(i).MapRange()
+		for iter.Next() {
+			merged.SetMapIndex(iter.Key(), iter.Value())
+		}
+		iter = g.Field(i).MapRange()
+		for iter.Next() {
+			existing := merged.MapIndex(iter.Key())
+			if !existing.IsValid() {
+				merged.SetMapIndex(iter.Key(), iter.Value())
+				continue
+			}
+			winner, _, err := m.resolve("component "+kind, iter.Key().String(), existing.Interface(), iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			merged.SetMapIndex(iter.Key(), reflect.ValueOf(winner))
+		}
+		r.Field(i).Set(merged)
+	}
+	return result, nil
+}
+
+func (m *merger) mergePaths(base, generated openapi3.Paths) (openapi3.Paths, error) {
+	paths := make(openapi3.Paths, len(base)+len(generated))
+	for path, pathItem := range base {
+		paths[path] = pathItem
+	}
+	for path, pathItem := range generated {
+		existing, ok := paths[path]
+		if !ok {
+			paths[path] = pathItem
+			continue
+		}
+		merged, err := m.mergePathItem(path, existing, pathItem)
+		if err != nil {
+			return nil, err
+		}
+		paths[path] = merged
+	}
+	return paths, nil
+}
+
+func (m *merger) mergePathItem(path string, base, generated *openapi3.PathItem) (*openapi3.PathItem, error) {
+	primary, secondary := base, generated
+	if m.strategy == MergeGeneratedWins {
+		primary, secondary = generated, base
+	}
+
+	result := &openapi3.PathItem{
+		Extensions:  mergeMaps(primary.Extensions, secondary.Extensions).(map[string]interface{}),
+		Ref:         primary.Ref,
+		Summary:     primary.Summary,
+		Description: primary.Description,
+		Servers:     primary.Servers,
+		Parameters:  mergeParameters(primary.Parameters, secondary.Parameters),
+	}
+	if result.Ref == "" {
+		result.Ref = secondary.Ref
+	}
+	if result.Summary == "" {
+		result.Summary = secondary.Summary
+	}
+	if result.Description == "" {
+		result.Description = secondary.Description
+	}
+	if len(result.Servers) == 0 {
+		result.Servers = secondary.Servers
+	}
+
+	baseOps, generatedOps := base.Operations(), generated.Operations()
+	for _, method := range sortedKeys(baseOps, generatedOps) {
+		baseOp, inBase := baseOps[method]
+		generatedOp, inGenerated := generatedOps[method]
+		switch {
+		case !inGenerated:
+			result.SetOperation(method, baseOp)
+		case !inBase:
+			result.SetOperation(method, generatedOp)
+		default:
+			winner, loser, err := m.resolve("operation", method+" "+path, baseOp, generatedOp)
+			if err != nil {
+				return nil, err
+			}
+			result.SetOperation(method, mergeOperation(winner.(*openapi3.Operation), loser.(*openapi3.Operation)))
+		}
+	}
+	return result, nil
+}
+
+// mergeOperation returns a new operation with the fields of the primary operation, completed
+// with the fields of the secondary operation that are not defined in the primary.
+func mergeOperation(primary, secondary *openapi3.Operation) *openapi3.Operation {
+	op := *primary
+	op.Extensions = mergeMaps(primary.Extensions, secondary.Extensions).(map[string]interface{})
+	op.Parameters = mergeParameters(primary.Parameters, secondary.Parameters)
+	op.Responses = mergeMaps(primary.Responses, secondary.Responses).(openapi3.Responses)
+	op.Callbacks = mergeMaps(primary.Callbacks, secondary.Callbacks).(openapi3.Callbacks)
+	op.Deprecated = primary.Deprecated || secondary.Deprecated
+	if op.Tags == nil {
+		op.Tags = secondary.Tags
+	}
+	if op.Summary == "" {
+		op.Summary = secondary.Summary
+	}
+	if op.Description == "" {
+		op.Description = secondary.Description
+	}
+	if op.OperationID == "" {
+		op.OperationID = secondary.OperationID
+	}
+	if op.RequestBody == nil {
+		op.RequestBody = secondary.RequestBody
+	}
+	if op.Security == nil {
+		op.Security = secondary.Security
+	}
+	if op.Servers == nil {
+		op.Servers = secondary.Servers
+	}
+	if op.ExternalDocs == nil {
+		op.ExternalDocs = secondary.ExternalDocs
+	}
+	return &op
+}
+
+// mergeParameters returns the primary parameters, followed by the secondary parameters
+// that don't have the same location and name as one of the primary parameters.
+func mergeParameters(primary, secondary openapi3.Parameters) openapi3.Parameters {
+	if len(primary) == 0 {
+		return secondary
+	}
+	parameters := append(openapi3.Parameters{}, primary...)
+	for _, p := range secondary {
+		if p.Value == nil || primary.GetByInAndName(p.Value.In, p.Value.Name) == nil {
+			parameters = append(parameters, p)
+		}
+	}
+	return parameters
+}
+
+// mergeMaps returns a new map containing the entries of both given maps, which must be
+// of the same type. The entries of the primary map win.
+// Returns nil (typed) if both maps are empty.
+func mergeMaps(primary, secondary interface{}) interface{} {
+	p, s := reflect.ValueOf(primary), reflect.ValueOf(secondary)
+	if p.Len() == 0 && s.Len() == 0 {
+		return reflect.Zero(p.Type()).Interface()
+	}
+	merged := reflect.MakeMapWithSize(p.Type(), p.Len()+s.Len())
+	iter := s.MapRange()
+	for iter.Next() {
+		merged.SetMapIndex(iter.Key(), iter.Value())
+	}
+	iter = p.MapRange()
+	for iter.Next() {
+		merged.SetMapIndex(iter.Key(), iter.Value())
+	}
+	return merged.Interface()
+}
+
+// mergeTags returns the base tags, followed by the generated tags that are not
+// in the base tags, sorted by name.
+func mergeTags(base, generated openapi3.Tags) openapi3.Tags {
+	if len(base) == 0 {
+		return generated
+	}
+	tags := append(openapi3.Tags{}, base...)
+	added := openapi3.Tags{}
+	for _, t := range generated {
+		if base.Get(t.Name) == nil {
+			added = append(added, t)
+		}
+	}
+	sort.Slice(added, func(i, j int) bool {
+		return added[i].Name < added[j].Name
+	})
+	return append(tags, added...)
+}
@@ -0,0 +1,210 @@
+package openapi3
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/suite"
+)
+
+type MergeTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MergeTestSuite) makeBase() *openapi3.T {
+	op := openapi3.NewOperation()
+	op.Description = "Base description"
+	op.Responses = openapi3.Responses{
+		"201": &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Created")},
+	}
+	return &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    &openapi3.Info{Title: "Hand-written", Version: "1.0.0"},
+		Paths: openapi3.Paths{
+			"/users":  &openapi3.PathItem{Post: op},
+			"/health": &openapi3.PathItem{Get: openapi3.NewOperation()},
+		},
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{
+				"Error": openapi3.NewObjectSchema().NewRef(),
+				"User":  openapi3.NewStringSchema().NewRef(),
+			},
+		},
+		Tags: openapi3.Tags{{Name: "users", Description: "Base tag"}},
+	}
+}
+
+func (suite *MergeTestSuite) makeGenerated() *openapi3.T {
+	op := openapi3.NewOperation()
+	op.Summary = "Generated summary"
+	op.Description = "Generated description"
+	op.OperationID = "user.Store"
+	op.AddParameter(openapi3.NewQueryParameter("page"))
+	op.AddResponse(0, openapi3.NewResponse().WithDescription(""))
+	return &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "Generator", Version: "0.0.0"},
+		Paths: openapi3.Paths{
+			"/users": &openapi3.PathItem{
+				Get:  openapi3.NewOperation(),
+				Post: op,
+			},
+		},
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{
+				"User":    openapi3.NewObjectSchema().NewRef(),
+				"Product": openapi3.NewObjectSchema().NewRef(),
+			},
+		},
+		Servers: openapi3.Servers{{URL: "http://localhost"}},
+		Tags:    openapi3.Tags{{Name: "users"}, {Name: "products"}},
+	}
+}
+
+func (suite *MergeTestSuite) TestMergeBaseWins() {
+	base, generated := suite.makeBase(), suite.makeGenerated()
+	spec, err := Merge(base, generated, MergeBaseWins)
+	suite.Require().NoError(err)
+
+	suite.Equal("3.0.3", spec.OpenAPI)
+	suite.Equal("Hand-written", spec.Info.Title)
+	suite.Equal(generated.Servers, spec.Servers)
+	suite.Equal(openapi3.Tags{{Name: "users", Description: "Base tag"}, {Name: "products"}}, spec.Tags)
+
+	suite.Len(spec.Components.Schemas, 3)
+	suite.Same(base.Components.Schemas["User"], spec.Components.Schemas["User"])
+	suite.Same(base.Components.Schemas["Error"], spec.Components.Schemas["Error"])
+	suite.Same(generated.Components.Schemas["Product"], spec.Components.Schemas["Product"])
+
+	suite.Len(spec.Paths, 2)
+	suite.Same(base.Paths["/health"], spec.Paths["/health"])
+	users := spec.Paths["/users"]
+	suite.Same(generated.Paths["/users"].Get, users.Get)
+
+	post := users.Post
+	suite.Equal("Generated summary", post.Summary)
+	suite.Equal("Base description", post.Description)
+	suite.Equal("user.Store", post.OperationID)
+	suite.Len(post.Parameters, 1)
+	suite.Contains(post.Responses, "201")
+	suite.Contains(post.Responses, "default")
+
+	// The given specs are not modified
+	suite.Len(base.Paths["/users"].Post.Responses, 1)
+	suite.Nil(base.Paths["/users"].Get)
+	suite.Len(base.Components.Schemas, 2)
+}
+
+func (suite *MergeTestSuite) TestMergeGeneratedWins() {
+	base, generated := suite.makeBase(), suite.makeGenerated()
+	spec, err := Merge(base, generated, MergeGeneratedWins)
+	suite.Require().NoError(err)
+
+	suite.Equal("Hand-written", spec.Info.Title)
+	suite.Same(generated.Components.Schemas["User"], spec.Components.Schemas["User"])
+	suite.Same(base.Components.Schemas["Error"], spec.Components.Schemas["Error"])
+
+	post := spec.Paths["/users"].Post
+	suite.Equal("Generated summary", post.Summary)
+	suite.Equal("Generated description", post.Description)
+	suite.Contains(post.Responses, "201")
+	suite.Contains(post.Responses, "default")
+}
+
+func (suite *MergeTestSuite) TestMergeError() {
+	_, err := Merge(suite.makeBase(), suite.makeGenerated(), MergeError)
+	suite.Require().Error(err)
+	suite.Equal(`openapi3: conflicting definitions of component schemas "User" in base document and generated spec`, err.Error())
+
+	base := suite.makeBase()
+	delete(base.Components.Schemas, "User")
+	_, err = Merge(base, suite.makeGenerated(), MergeError)
+	suite.Require().Error(err)
+	suite.Equal(`openapi3: conflicting definitions of operation "POST /users" in base document and generated spec`, err.Error())
+
+	delete(base.Paths, "/users")
+	spec, err := Merge(base, suite.makeGenerated(), MergeError)
+	suite.Require().NoError(err)
+	suite.Len(spec.Paths, 2)
+}
+
+func (suite *MergeTestSuite) TestMergeErrorExtend() {
+	bodyRef := "#/components/requestBodies/UserStore"
+	body := openapi3.NewRequestBody().WithJSONSchema(openapi3.NewObjectSchema())
+
+	// A loaded base has resolved refs, a generated spec doesn't
+	baseOp := openapi3.NewOperation()
+	baseOp.RequestBody = &openapi3.RequestBodyRef{Ref: bodyRef, Value: body}
+	baseOp.Responses = openapi3.Responses{
+		"201": &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("Created")},
+	}
+	baseOp.AddParameter(openapi3.NewHeaderParameter("X-Tenant"))
+	base := &openapi3.T{
+		Paths: openapi3.Paths{"/users": &openapi3.PathItem{Post: baseOp}},
+		Components: &openapi3.Components{
+			RequestBodies: openapi3.RequestBodies{"UserStore": &openapi3.RequestBodyRef{Value: body}},
+		},
+	}
+
+	generatedOp := openapi3.NewOperation()
+	generatedOp.OperationID = "user.Store"
+	generatedOp.RequestBody = &openapi3.RequestBodyRef{Ref: bodyRef}
+	generatedOp.Parameters = openapi3.Parameters{{Ref: "#/components/parameters/page"}}
+	generatedOp.AddResponse(0, openapi3.NewResponse().WithDescription(""))
+	generated := &openapi3.T{
+		Paths: openapi3.Paths{"/users": &openapi3.PathItem{Post: generatedOp}},
+		Components: &openapi3.Components{
+			RequestBodies: openapi3.RequestBodies{"UserStore": &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithJSONSchema(openapi3.NewObjectSchema())}},
+		},
+	}
+
+	spec, err := Merge(base, generated, MergeError)
+	suite.Require().NoError(err)
+	post := spec.Paths["/users"].Post
+	suite.Equal("user.Store", post.OperationID)
+	suite.Contains(post.Responses, "201")
+	suite.Contains(post.Responses, "default")
+	suite.Len(post.Parameters, 2)
+
+	// Same parameter with a different definition
+	generatedOp.AddParameter(openapi3.NewHeaderParameter("X-Tenant").WithRequired(true))
+	_, err = Merge(base, generated, MergeError)
+	suite.Require().Error(err)
+}
+
+func (suite *MergeTestSuite) TestMergeEmptyBase() {
+	generated := suite.makeGenerated()
+	spec, err := Merge(&openapi3.T{}, generated, MergeBaseWins)
+	suite.Require().NoError(err)
+	suite.Equal("3.0.0", spec.OpenAPI)
+	suite.Same(generated.Info, spec.Info)
+	suite.Same(generated.Components, spec.Components)
+	suite.Equal(generated.Tags, spec.Tags)
+	suite.Len(spec.Paths, 1)
+}
+
+func (suite *MergeTestSuite) TestMergeParameters() {
+	page := &openapi3.ParameterRef{Value: openapi3.NewQueryParameter("page").WithDescription("primary")}
+	otherPage := &openapi3.ParameterRef{Value: openapi3.NewQueryParameter("page")}
+	id := &openapi3.ParameterRef{Value: openapi3.NewPathParameter("id")}
+
+	suite.Equal(openapi3.Parameters{page, id}, mergeParameters(openapi3.Parameters{page}, openapi3.Parameters{otherPage, id}))
+	suite.Equal(openapi3.Parameters{id}, mergeParameters(nil, openapi3.Parameters{id}))
+}
+
+func (suite *MergeTestSuite) TestLoadSpec() {
+	path := filepath.Join(suite.T().TempDir(), "base.yaml")
+	suite.Require().NoError(os.WriteFile(path, []byte("openapi: 3.0.0\ninfo:\n  title: Base\n  version: 1.0.0\npaths: {}\n"), 0644))
+	spec, err := LoadSpec(path)
+	suite.Require().NoError(err)
+	suite.Equal("Base", spec.Info.Title)
+
+	_, err = LoadSpec(filepath.Join(suite.T().TempDir(), "notafile.yaml"))
+	suite.Error(err)
+}
+
+func TestMergeSuite(t *testing.T) {
+	suite.Run(t, new(MergeTestSuite))
+}
@@ -0,0 +1,236 @@
+package openapi3
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"goyave.dev/goyave/v4"
+)
+
+// MiddlewareDoc documentation of the parameters a middleware reads and of the
+// response headers it adds.
+type MiddlewareDoc struct {
+	// Parameters header, query or cookie parameters read by the middleware.
+	Parameters openapi3.Parameters
+
+	// ResponseHeaders headers added by the middleware to the responses, indexed by name.
+	ResponseHeaders openapi3.Headers
+}
+
+// MiddlewareRegistry associates middleware to their documentation. The parameters and
+// response headers of the middleware applied to a route (including the middleware of
+// its parent routers) are added to the operations generated from this route.
+//
+// A MiddlewareRegistry is safe for concurrent use.
+type MiddlewareRegistry struct {
+	docs map[uintptr]*MiddlewareDoc
+	mu   sync.RWMutex
+}
+
+// builtinMiddleware documentation of goyave's middleware, indexed by function name
+// because some of them are not exported.
+var builtinMiddleware = map[string]*MiddlewareDoc{
+	"goyave.dev/goyave/v4.languageMiddleware": {
+		Parameters: openapi3.Parameters{
+			{Value: openapi3.NewHeaderParameter("Accept-Language").
+				WithDescription("Language of the response messages. Defaults to the application's default language.").
+				WithSchema(openapi3.NewStringSchema())},
+		},
+	},
+	"goyave.dev/goyave/v4/middleware.GzipLevel.func1": gzipMiddlewareDoc,
+	"goyave.dev/goyave/v4/middleware.Gzip.func1":      gzipMiddlewareDoc,
+}
+
+var gzipMiddlewareDoc = &MiddlewareDoc{
+	Parameters: openapi3.Parameters{
+		{Value: openapi3.NewHeaderParameter("Accept-Encoding").
+			WithDescription("The response is compressed if it contains `gzip`.").
+			WithSchema(openapi3.NewStringSchema())},
+	},
+	ResponseHeaders: openapi3.Headers{
+		"Content-Encoding": newHeader("`gzip` if the response is compressed.", openapi3.NewStringSchema()),
+	},
+}
+
+// NewMiddlewareRegistry create a new MiddlewareRegistry. goyave's own middleware
+// (language, gzip) are documented out of the box.
+func NewMiddlewareRegistry() *MiddlewareRegistry {
+	return &MiddlewareRegistry{
+		docs: make(map[uintptr]*MiddlewareDoc),
+	}
+}
+
+// Register the documentation of the given middleware. Middleware created by the same
+// function literal share the same documentation, so registering one instance is enough.
+func (r *MiddlewareRegistry) Register(middleware goyave.Middleware, doc *MiddlewareDoc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.docs[reflect.ValueOf(middleware).Pointer()] = doc
+}
+
+// Get the documentation of the given middleware. Returns nil if the middleware
+// is not documented. If the registry is nil, only goyave's middleware are documented.
+func (r *MiddlewareRegistry) Get(middleware goyave.Middleware) *MiddlewareDoc {
+	return r.get(reflect.ValueOf(middleware).Pointer())
+}
+
+func (r *MiddlewareRegistry) get(pc uintptr) *MiddlewareDoc {
+	if r != nil {
+		r.mu.RLock()
+		doc, ok := r.docs[pc]
+		r.mu.RUnlock()
+		if ok {
+			return doc
+		}
+	}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		return builtinMiddleware[fn.Name()]
+	}
+	return nil
+}
+
+// routeMiddleware returns the function pointers of the middleware applied to the
+// given route, in the order they are executed: global middleware first, then the
+// middleware of the routers from the root, then the route's middleware.
+func routeMiddleware(route *goyave.Route) []uintptr {
+	routers := []*goyave.Router{}
+	for router := route.GetParent(); router != nil; router = router.GetParent() {
+		routers = append(routers, router)
+	}
+
+	middleware := []uintptr{}
+	if len(routers) > 0 {
+		middleware = append(middleware, globalMiddleware(routers[len(routers)-1])...)
+	}
+	for i := len(routers) - 1; i >= 0; i-- {
+		middleware = appendMiddleware(middleware, routers[i].GetMiddleware())
+	}
+	return appendMiddleware(middleware, route.GetMiddleware())
+}
+
+func appendMiddleware(pointers []uintptr, middleware []goyave.Middleware) []uintptr {
+	for _, m := range middleware {
+		pointers = append(pointers, reflect.ValueOf(m).Pointer())
+	}
+	return pointers
+}
+
+// globalMiddleware returns the function pointers of the global middleware of the
+// given router.
+//
+// goyave doesn't expose the global middleware of a router: they are read from the
+// unexported "globalMiddleware" field. Only the function pointers are read, which
+// doesn't require unsafe. TestGlobalMiddleware fails if the field changes.
+func globalMiddleware(router *goyave.Router) []uintptr {
+	holder := reflect.ValueOf(router).Elem().FieldByName("globalMiddleware")
+	if !holder.IsValid() || holder.Kind() != reflect.Ptr || holder.IsNil() {
+		return nil
+	}
+	field := holder.Elem().FieldByName("middleware")
+	if !field.IsValid() || field.Kind() != reflect.Slice {
+		return nil
+	}
+	middleware := make([]uintptr, 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		middleware = append(middleware, field.Index(i).Pointer())
+	}
+	return middleware
+}
+
+// convertMiddleware adds the parameters and response headers of the documented
+// middleware applied to the route to the given operation. Parameters already
+// defined on the operation are not overridden.
+func (c *RouteConverter) convertMiddleware(op *openapi3.Operation, spec *openapi3.T) {
+	for _, pc := range routeMiddleware(c.route) {
+		doc := c.generator.Middleware.get(pc)
+		if doc == nil {
+			continue
+		}
+		for _, p := range doc.Parameters {
+			if param := resolveParameter(p, spec); param != nil && hasParameter(op, param.In, param.Name, spec) {
+				continue
+			}
+			op.Parameters = append(op.Parameters, c.middlewareParameter(p, spec))
+		}
+		for _, response := range op.Responses {
+			if response.Value == nil || len(doc.ResponseHeaders) == 0 {
+				continue
+			}
+			if response.Value.Headers == nil {
+				response.Value.Headers = make(openapi3.Headers, len(doc.ResponseHeaders))
+			}
+			for name, header := range doc.ResponseHeaders {
+				if _, exists := response.Value.Headers[name]; !exists {
+					response.Value.Headers[name] = header
+				}
+			}
+		}
+	}
+}
+
+// middlewareParameter returns a ref to the component generated from the given middleware
+// parameter. The component is generated once per spec from a copy of the parameter, so
+// the documentation of the middleware is never modified by plugins. Parameters that are
+// already refs are returned as is.
+func (c *RouteConverter) middlewareParameter(p *openapi3.ParameterRef, spec *openapi3.T) *openapi3.ParameterRef {
+	if p.Ref != "" || p.Value == nil {
+		return p
+	}
+	if ref, ok := c.refs.MiddlewareParameters[p]; ok {
+		return ref
+	}
+
+	param := *p.Value
+	if param.Schema != nil && param.Schema.Ref == "" && param.Schema.Value != nil {
+		schema := *param.Schema.Value
+		param.Schema = schema.NewRef()
+	}
+	c.generator.plugins.parameter(c.route, &param)
+	paramRef := &openapi3.ParameterRef{Value: &param}
+
+	baseName := param.In + "-" + param.Name
+	name := baseName
+	for i := 2; ; i++ {
+		existing, exists := spec.Components.Parameters[name]
+		if !exists {
+			spec.Components.Parameters[name] = paramRef
+			break
+		}
+		if jsonEqual(existing, paramRef) { // Restored from the generation cache
+			break
+		}
+		name = fmt.Sprintf("%s.%d", baseName, i)
+	}
+
+	ref := &openapi3.ParameterRef{Ref: "#/components/parameters/" + name}
+	c.refs.MiddlewareParameters[p] = ref
+	return ref
+}
+
+// resolveParameter returns the value of the given parameter, looked up in the
+// components of the spec if the parameter is a ref. Returns nil if the parameter
+// cannot be resolved.
+func resolveParameter(p *openapi3.ParameterRef, spec *openapi3.T) *openapi3.Parameter {
+	if p.Value != nil || !strings.HasPrefix(p.Ref, "#/components/parameters/") {
+		return p.Value
+	}
+	if component, ok := spec.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]; ok {
+		return component.Value
+	}
+	return nil
+}
+
+// hasParameter returns true if the given operation has a parameter with the given
+// location and name, including the parameters that are refs to components.
+func hasParameter(op *openapi3.Operation, in, name string, spec *openapi3.T) bool {
+	for _, p := range op.Parameters {
+		if param := resolveParameter(p, spec); param != nil && param.In == in && param.Name == name {
+			return true
+		}
+	}
+	return false
+}
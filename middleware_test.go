@@ -0,0 +1,200 @@
+package openapi3
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+	"goyave.dev/goyave/v4/middleware"
+	"goyave.dev/goyave/v4/validation"
+)
+
+func requestIDMiddleware(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		next(response, request)
+	}
+}
+
+func idempotencyMiddleware(next goyave.Handler) goyave.Handler {
+	return next
+}
+
+func reflectPointer(middleware goyave.Middleware) uintptr {
+	return reflect.ValueOf(middleware).Pointer()
+}
+
+type describingPlugin struct {
+	BasePlugin
+	calls int
+}
+
+func (p *describingPlugin) Parameter(_ *goyave.Route, param *openapi3.Parameter) {
+	if param.In == openapi3.ParameterInHeader {
+		p.calls++
+		param.Description = "Described by plugin"
+	}
+}
+
+type MiddlewareTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *MiddlewareTestSuite) makeRegistry() *MiddlewareRegistry {
+	registry := NewMiddlewareRegistry()
+	registry.Register(requestIDMiddleware, &MiddlewareDoc{
+		Parameters: openapi3.Parameters{
+			{Value: openapi3.NewHeaderParameter("X-Request-ID").WithRequired(true).WithSchema(openapi3.NewStringSchema())},
+		},
+		ResponseHeaders: openapi3.Headers{
+			"X-Request-ID": newHeader("The ID of the request.", openapi3.NewStringSchema()),
+		},
+	})
+	registry.Register(idempotencyMiddleware, &MiddlewareDoc{
+		Parameters: openapi3.Parameters{
+			{Value: openapi3.NewHeaderParameter("Idempotency-Key").WithSchema(openapi3.NewStringSchema())},
+			{Value: openapi3.NewHeaderParameter("X-Request-ID")},
+		},
+	})
+	return registry
+}
+
+func (suite *MiddlewareTestSuite) TestRegistry() {
+	registry := suite.makeRegistry()
+	suite.NotNil(registry.Get(requestIDMiddleware))
+	suite.Nil(registry.Get(middleware.Trim))
+
+	var nilRegistry *MiddlewareRegistry
+	suite.Nil(nilRegistry.Get(requestIDMiddleware))
+}
+
+func (suite *MiddlewareTestSuite) TestBuiltinMiddleware() {
+	suite.Same(gzipMiddlewareDoc, NewMiddlewareRegistry().Get(middleware.Gzip()))
+
+	router := goyave.NewRouter()
+	router.Get("/users", HandlerTest)
+	router.Post("/users", HandlerTest)
+	spec := NewGenerator().Generate(router)
+
+	// Generated once in the components and referenced by all the operations
+	suite.Require().Contains(spec.Components.Parameters, "header-Accept-Language")
+	suite.Equal("Accept-Language", spec.Components.Parameters["header-Accept-Language"].Value.Name)
+	expected := openapi3.Parameters{{Ref: "#/components/parameters/header-Accept-Language"}}
+	suite.Equal(expected, spec.Paths["/users"].Get.Parameters)
+	suite.Equal(expected, spec.Paths["/users"].Post.Parameters)
+}
+
+func (suite *MiddlewareTestSuite) TestMiddlewareParameterPlugin() {
+	router := goyave.NewRouter()
+	router.Get("/users", HandlerTest)
+	router.Post("/users", HandlerTest)
+
+	plugin := &describingPlugin{}
+	generator := NewGenerator()
+	generator.AddPlugin(plugin)
+	spec := generator.Generate(router)
+
+	suite.Equal(1, plugin.calls)
+	suite.Equal("Described by plugin", spec.Components.Parameters["header-Accept-Language"].Value.Description)
+
+	// The documentation of goyave's middleware is not modified
+	doc := builtinMiddleware["goyave.dev/goyave/v4.languageMiddleware"]
+	suite.NotEqual("Described by plugin", doc.Parameters[0].Value.Description)
+	spec = NewGenerator().Generate(router)
+	suite.NotEqual("Described by plugin", spec.Components.Parameters["header-Accept-Language"].Value.Description)
+}
+
+func (suite *MiddlewareTestSuite) TestGlobalMiddleware() {
+	router := goyave.NewRouter()
+	router.GlobalMiddleware(requestIDMiddleware)
+
+	// Fails if goyave's Router doesn't store its global middleware in the
+	// "globalMiddleware" field anymore.
+	suite.Contains(globalMiddleware(router), reflectPointer(requestIDMiddleware), "the global middleware of goyave's Router cannot be read anymore")
+
+	route := router.Subrouter("/users").Get("/", HandlerTest)
+	suite.Contains(routeMiddleware(route), reflectPointer(requestIDMiddleware))
+}
+
+func (suite *MiddlewareTestSuite) TestMiddlewareQueryParameterDuplicate() {
+	registry := NewMiddlewareRegistry()
+	registry.Register(idempotencyMiddleware, &MiddlewareDoc{
+		Parameters: openapi3.Parameters{
+			{Value: openapi3.NewQueryParameter("page").WithSchema(openapi3.NewIntegerSchema())},
+		},
+	})
+	router := goyave.NewRouter()
+	router.Get("/users", HandlerTest).Middleware(idempotencyMiddleware).Validate(&validation.Rules{
+		Fields: validation.FieldMap{
+			"page": &validation.Field{Rules: []*validation.Rule{{Name: "integer"}}},
+		},
+	})
+
+	generator := NewGenerator()
+	generator.Middleware = registry
+	spec := generator.Generate(router)
+
+	count := 0
+	for _, p := range spec.Paths["/users"].Get.Parameters {
+		if param := resolveParameter(p, spec); param.In == openapi3.ParameterInQuery && param.Name == "page" {
+			count++
+		}
+	}
+	suite.Equal(1, count)
+	suite.NotContains(spec.Components.Parameters, "query-page")
+}
+
+func (suite *MiddlewareTestSuite) TestRouteMiddleware() {
+	router := goyave.NewRouter()
+	router.Middleware(requestIDMiddleware)
+	subrouter := router.Subrouter("/users")
+	subrouter.Middleware(middleware.Trim)
+	route := subrouter.Get("/", HandlerTest).Middleware(idempotencyMiddleware)
+
+	mw := routeMiddleware(route)
+	suite.GreaterOrEqual(len(mw), 3)
+	suite.Equal(reflectPointer(requestIDMiddleware), mw[len(mw)-3])
+	suite.Equal(reflectPointer(middleware.Trim), mw[len(mw)-2])
+	suite.Equal(reflectPointer(idempotencyMiddleware), mw[len(mw)-1])
+}
+
+func (suite *MiddlewareTestSuite) TestGenerateMiddlewareParameters() {
+	router := goyave.NewRouter()
+	subrouter := router.Subrouter("/users")
+	subrouter.Middleware(requestIDMiddleware)
+	subrouter.Post("/", HandlerTest).Middleware(idempotencyMiddleware)
+	router.Get("/products", HandlerTest)
+
+	generator := NewGenerator()
+	generator.Middleware = suite.makeRegistry()
+	spec := generator.Generate(router)
+
+	op := spec.Paths["/users"].Post
+	suite.Contains(op.Parameters, &openapi3.ParameterRef{Ref: "#/components/parameters/header-X-Request-ID"})
+	suite.True(spec.Components.Parameters["header-X-Request-ID"].Value.Required) // Not overridden by the second middleware
+	suite.NotContains(spec.Components.Parameters, "header-X-Request-ID.2")
+	suite.True(hasParameter(op, openapi3.ParameterInHeader, "Idempotency-Key", spec))
+	suite.Contains(op.Responses["default"].Value.Headers, "X-Request-ID")
+
+	other := spec.Paths["/products"].Get
+	suite.False(hasParameter(other, openapi3.ParameterInHeader, "X-Request-ID", spec))
+	suite.NotContains(other.Responses["default"].Value.Headers, "X-Request-ID")
+}
+
+func TestMiddlewareSuite(t *testing.T) {
+	if err := config.LoadJSON(`{
+			"app": {
+				"name": "Generator"
+			},
+			"server": {
+				"protocol": "http",
+				"domain": "goyave.dev",
+				"port": 80
+			}
+		}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(MiddlewareTestSuite))
+}
@@ -10,18 +10,101 @@ import (
 )
 
 // Generator for OpenAPI 3 specification based on Router.
+//
+// A Generator can be used multiple times and concurrently, as long as it is not
+// modified while generating. Plugins must be safe for concurrent use if specs are
+// generated concurrently.
 type Generator struct {
-	spec *openapi3.T
-	refs *Refs
+	// TagFunc returns the tag of the operations generated from a route.
+	// If `nil`, the first segment of the route's URI is used (TagFirstSegment).
+	//
+	// Use TagControllerPackage to group the operations by controller package. It is not
+	// the default because it changes the tags of existing specs, and because it produces
+	// a single tag when the handlers are not split into packages (closures, handlers
+	// declared in the main package).
+	TagFunc TagFunc
+
+	// TagDescriptions the descriptions of the tags, indexed by tag name.
+	TagDescriptions map[string]string
+
+	// TagGroups groups of tags, generated in the "x-tagGroups" extension
+	// supported by some documentation tools.
+	TagGroups []*TagGroup
+
+	// OperationIDFunc returns the operationId of the operations generated from a route.
+	// If `nil`, DefaultOperationID is used.
+	OperationIDFunc OperationIDFunc
+
+	// Base hand-written document the generated paths and components are merged into.
+	// Can be loaded from a file using LoadSpec. See Merge for more details.
+	Base *openapi3.T
+
+	// MergeStrategy defines how conflicts between Base and the generated spec are resolved.
+	// Defaults to MergeBaseWins.
+	MergeStrategy MergeStrategy
+
+	// Patches applied to the generated spec, in order, after it has been merged into
+	// Base. For example Overlay or JSONPatch documents.
+	Patches []Patch
+
+	// Cache the results of the source code analysis, shared by all the
+	// specs generated. Can be shared by multiple generators.
+	Cache *Cache
+
+	// Middleware documentation of the middleware: the parameters and response headers
+	// of the middleware applied to a route are added to its operations.
+	Middleware *MiddlewareRegistry
+
+	// IncludeHeadAndOptions if true, operations are generated for the HEAD and OPTIONS
+	// methods of the routes. OPTIONS operations of routers with CORS options are
+	// documented as preflight requests.
+	IncludeHeadAndOptions bool
+
+	// ExcludeStatic if true, the routes registered using goyave.Router.Static
+	// are not included in the generated specs.
+	ExcludeStatic bool
+
+	// CacheDir if not empty, the generated spec and the documentation of the handlers
+	// are stored in this directory and reused by the next generations if the router
+	// didn't change. The fingerprint of the router is computed from the routes (URI,
+	// methods, name, validation rules, middleware and their documentation, CORS options),
+	// the handlers' source files, their package documentation and the generator's settings.
+	// If the fingerprint doesn't match, only the routes whose fingerprint changed are
+	// converted again, and only the handlers whose source file changed are analyzed again.
+	// Errors when reading or writing the cache are ignored: the spec is generated anyway.
+	//
+	// Functions (TagFunc, OperationIDFunc) are not part of the fingerprint: clear the
+	// cache directory when they change. Plugins
+	// cannot be fingerprinted: if the generator has plugins, the spec is always generated
+	// and only the documentation of the handlers is reused. Base and Patches are always
+	// applied.
+	CacheDir string
+
+	securitySchemes []*securityScheme
+	excluded        []*goyave.Router
+	plugins         pluginList
 }
 
 // NewGenerator create a new OpenAPI 3 specification Generator.
 func NewGenerator() *Generator {
 	return &Generator{
-		refs: NewRefs(),
+		Cache:      NewCache(),
+		Middleware: NewMiddlewareRegistry(),
 	}
 }
 
+// generation state of a single Generate call.
+type generation struct {
+	*Generator
+	operationIDs      map[string]struct{}
+	spec              *openapi3.T
+	refs              *Refs
+	fingerprint       string
+	routeFingerprints map[*goyave.Route]*routeFingerprint
+	cachedRoutes      map[string]*cachedRoute
+	generatedRoutes   map[string]*cachedRoute
+}
+
 // Generate an OpenAPI 3 specification based on the given Router.
 //
 // Goyave config will be loaded (if not already).
@@ -30,11 +113,80 @@ func NewGenerator() *Generator {
 // from the config.
 // Servers section will be filled using the configuration as well, thanks to the
 // goyave.BaseURL() function.
+//
+// If the generator has a Base document, the generated spec is merged into it.
+// The Patches are then applied.
+//
+// If an error occurs, it is printed and nil is returned. Use GenerateE to handle
+// the error.
 func (g *Generator) Generate(router *goyave.Router) *openapi3.T {
-	if err := loadConfig(); err != nil {
+	spec, err := g.GenerateE(router)
+	if err != nil {
 		fmt.Println(err)
 		return nil
 	}
+	return spec
+}
+
+// GenerateE is the same as Generate but returns the error instead of printing it:
+// the config cannot be loaded, the source file of a handler cannot be parsed, the
+// generation cache cannot be used, the spec cannot be merged into Base or a patch
+// cannot be applied.
+func (g *Generator) GenerateE(router *goyave.Router) (*openapi3.T, error) {
+	if err := loadConfig(); err != nil {
+		return nil, err
+	}
+	cache := g.Cache
+	if cache == nil {
+		cache = NewCache()
+	}
+	gen := &generation{
+		Generator:    g,
+		operationIDs: make(map[string]struct{}),
+		refs:         newRefs(cache),
+	}
+	var spec *openapi3.T
+	if g.CacheDir != "" {
+		var err error
+		spec, err = gen.loadDiskCache(router)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if spec == nil {
+		var err error
+		spec, err = gen.generate(router)
+		if err != nil {
+			return nil, err
+		}
+		if g.CacheDir != "" {
+			// The spec is valid even if it cannot be cached (read-only file system)
+			if err := gen.saveDiskCache(); err != nil {
+				fmt.Println(fmt.Errorf("openapi3: could not write the cache: %w", err))
+			}
+		}
+	}
+
+	if g.Base != nil {
+		merged, err := Merge(g.Base, spec, g.MergeStrategy)
+		if err != nil {
+			return nil, err
+		}
+		spec = merged
+	}
+
+	for _, patch := range g.Patches {
+		patched, err := patch.Apply(spec)
+		if err != nil {
+			return nil, err
+		}
+		spec = patched
+	}
+
+	return spec, nil
+}
+
+func (g *generation) generate(router *goyave.Router) (*openapi3.T, error) {
 	g.spec = &openapi3.T{
 		OpenAPI: "3.0.0",
 		Info: &openapi3.Info{
@@ -51,14 +203,51 @@ func (g *Generator) Generate(router *goyave.Router) *openapi3.T {
 		},
 	}
 
+	g.plugins.init(g.spec)
+	g.convertSecuritySchemes()
+	if err := g.refs.parseFiles(g.refs.collectHandlerFiles(router, g.excluded)); err != nil {
+		return nil, err
+	}
 	g.convertRouter(router)
+	g.convertTags()
+	g.plugins.finalize(g.spec)
+	return g.spec, nil
+}
+
+// Exclude the given router, its routes and its subrouters from the generated specs.
+func (g *Generator) Exclude(router *goyave.Router) {
+	g.excluded = append(g.excluded, router)
+}
 
-	return g.spec
+// excluding returns a shallow copy of the generator also excluding the given router.
+// The generator's exclusion list is not modified.
+func (g *Generator) excluding(router *goyave.Router) *Generator {
+	copied := *g
+	copied.excluded = make([]*goyave.Router, 0, len(g.excluded)+1)
+	copied.excluded = append(copied.excluded, g.excluded...)
+	copied.excluded = append(copied.excluded, router)
+	return &copied
 }
 
-func (g *Generator) convertRouter(router *goyave.Router) {
+func (g *generation) convertRouter(router *goyave.Router) {
+	for _, excluded := range g.excluded {
+		if excluded == router {
+			return
+		}
+	}
+
 	for _, route := range router.GetRoutes() {
-		NewRouteConverter(route, g.refs).Convert(g.spec)
+		if g.ExcludeStatic && isStaticRoute(route) {
+			continue
+		}
+		if g.restoreRoute(route) {
+			continue
+		}
+		existing := operations(g.spec.Paths[cleanPath(route)])
+		g.plugins.beforeRoute(route, g.spec)
+		g.newRouteConverter(route).Convert(g.spec)
+		g.plugins.afterRoute(route, g.spec)
+		g.captureRoute(route, existing)
 	}
 
 	for _, subrouter := range router.GetSubrouters() {
@@ -66,6 +255,12 @@ func (g *Generator) convertRouter(router *goyave.Router) {
 	}
 }
 
+func (g *generation) newRouteConverter(route *goyave.Route) *RouteConverter {
+	converter := NewRouteConverter(route, g.refs)
+	converter.generator = g
+	return converter
+}
+
 func loadConfig() error {
 	if !config.IsLoaded() {
 		return config.Load()
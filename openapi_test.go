@@ -2,11 +2,14 @@ package openapi3
 
 import (
 	"net/http"
+	"sync"
 	"testing"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/assert"
 	"goyave.dev/goyave/v4"
 	"goyave.dev/goyave/v4/config"
+	"goyave.dev/goyave/v4/validation"
 )
 
 type OpenAPITestSuite struct {
@@ -16,7 +19,161 @@ type OpenAPITestSuite struct {
 func (suite *OpenAPITestSuite) TestNewGenerator() {
 	generator := NewGenerator()
 	suite.NotNil(generator)
-	suite.NotNil(generator.refs)
+	suite.NotNil(generator.Cache)
+}
+
+func (suite *OpenAPITestSuite) TestExclude() {
+	router := goyave.NewRouter()
+	router.Get("/included", HandlerTest)
+	subrouter := router.Subrouter("/excluded")
+	subrouter.Get("/", HandlerTest)
+	subrouter.Subrouter("/nested").Get("/", HandlerTest)
+
+	generator := NewGenerator()
+	generator.Exclude(subrouter)
+	spec := generator.Generate(router)
+	suite.Contains(spec.Paths, "/included")
+	suite.Len(spec.Paths, 1)
+}
+
+func (suite *OpenAPITestSuite) TestExcluding() {
+	router := goyave.NewRouter()
+	router.Get("/included", HandlerTest)
+	first := router.Subrouter("/first")
+	first.Get("/", HandlerTest)
+	second := router.Subrouter("/second")
+	second.Get("/", HandlerTest)
+
+	generator := NewGenerator()
+	generator.Exclude(first)
+	spec := generator.excluding(second).Generate(router)
+	suite.Equal([]*goyave.Router{first}, generator.excluded)
+	suite.Len(spec.Paths, 1)
+	suite.Contains(spec.Paths, "/included")
+
+	spec = generator.Generate(router)
+	suite.Len(spec.Paths, 2)
+	suite.Contains(spec.Paths, "/second")
+}
+
+func (suite *OpenAPITestSuite) TestGenerateWithBase() {
+	router := goyave.NewRouter()
+	router.Get("/users", HandlerTest)
+
+	generator := NewGenerator()
+	generator.Base = &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    &openapi3.Info{Title: "Base", Version: "1.0.0"},
+		Paths: openapi3.Paths{
+			"/users": &openapi3.PathItem{
+				Get: &openapi3.Operation{Summary: "List users"},
+			},
+		},
+	}
+	spec := generator.Generate(router)
+	suite.Equal("Base", spec.Info.Title)
+	suite.Equal("List users", spec.Paths["/users"].Get.Summary)
+	suite.Contains(spec.Paths["/users"].Get.Responses, "default")
+
+	generator.MergeStrategy = MergeError
+	suite.Nil(generator.Generate(router))
+	spec, err := generator.GenerateE(router)
+	suite.Nil(spec)
+	suite.Equal(`openapi3: conflicting definitions of operation "GET /users" in base document and generated spec`, err.Error())
+}
+
+func (suite *OpenAPITestSuite) TestGenerateWithPatches() {
+	router := goyave.NewRouter()
+	router.Get("/users", HandlerTest)
+	router.Get("/admin", HandlerTest)
+
+	generator := NewGenerator()
+	generator.Patches = []Patch{
+		&Overlay{Actions: []OverlayAction{{Target: "$.paths['/admin']", Remove: true}}},
+		JSONPatch{{Op: "replace", Path: "/info/version", Value: "1.0.0"}},
+	}
+	spec := generator.Generate(router)
+	suite.Contains(spec.Paths, "/users")
+	suite.NotContains(spec.Paths, "/admin")
+	suite.Equal("1.0.0", spec.Info.Version)
+
+	generator.Patches = []Patch{JSONPatch{{Op: "remove", Path: "/unknown"}}}
+	suite.Nil(generator.Generate(router))
+}
+
+func (suite *OpenAPITestSuite) makeRulesRouter(rules *validation.Rules, uri string) *goyave.Router {
+	router := goyave.NewRouter()
+	router.Post(uri, HandlerTest).Validate(rules)
+	return router
+}
+
+func (suite *OpenAPITestSuite) TestGenerateRepeated() {
+	rules := &validation.Rules{
+		Fields: validation.FieldMap{
+			"name": &validation.Field{Rules: []*validation.Rule{{Name: "string"}}},
+		},
+	}
+
+	generator := NewGenerator()
+	first := generator.Generate(suite.makeRulesRouter(rules, "/users"))
+	second := generator.Generate(suite.makeRulesRouter(rules, "/products"))
+
+	suite.Contains(first.Components.RequestBodies, "HandlerTest")
+	suite.Contains(second.Components.RequestBodies, "HandlerTest")
+	suite.NotSame(first.Components.RequestBodies["HandlerTest"], second.Components.RequestBodies["HandlerTest"])
+	suite.Equal("#/components/requestBodies/HandlerTest", second.Paths["/products"].Post.RequestBody.Ref)
+	suite.NotContains(second.Paths, "/users")
+	suite.Equal("openapi3.HandlerTest", second.Paths["/products"].Post.OperationID)
+	suite.NotEmpty(generator.Cache.HandlerDocs)
+}
+
+func (suite *OpenAPITestSuite) TestGenerateConcurrent() {
+	rules := &validation.Rules{
+		Fields: validation.FieldMap{
+			"name": &validation.Field{Rules: []*validation.Rule{{Name: "string"}}},
+		},
+	}
+
+	cache := NewCache()
+	generator := NewGenerator()
+	generator.Cache = cache
+	other := NewGenerator()
+	other.Cache = cache
+
+	// Validate() checks the rules: build the routers first so only
+	// Generate is called concurrently.
+	specs := make([]*openapi3.T, 8)
+	routers := make([]*goyave.Router, len(specs))
+	for i := range routers {
+		routers[i] = suite.makeRulesRouter(rules, "/users")
+	}
+
+	wg := sync.WaitGroup{}
+	for i := range specs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			g := generator
+			if i%2 == 0 {
+				g = other
+			}
+			specs[i] = g.Generate(routers[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, spec := range specs {
+		suite.Contains(spec.Paths, "/users")
+		suite.Contains(spec.Components.RequestBodies, "HandlerTest")
+		suite.Equal("openapi3.HandlerTest", spec.Paths["/users"].Post.OperationID)
+	}
+}
+
+func (suite *OpenAPITestSuite) TestGenerateWithoutCache() {
+	router := goyave.NewRouter()
+	router.Get("/users", HandlerTest)
+	generator := &Generator{}
+	suite.Contains(generator.Generate(router).Paths, "/users")
 }
 
 func (suite *OpenAPITestSuite) TestMakeServers() {
@@ -0,0 +1,82 @@
+package openapi3
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"goyave.dev/goyave/v4"
+)
+
+var closureSegmentFormat = regexp.MustCompile(`^func[0-9]+$`)
+
+// OperationIDFunc returns the operationId of the operation generated from the given route
+// and method. The given funcName is the full name of the route's handler, as returned by
+// "runtime.FuncForPC()". The generator ensures the returned IDs are unique across the spec
+// by appending a number to duplicates.
+type OperationIDFunc func(route *goyave.Route, method, funcName string) string
+
+// DefaultOperationID OperationIDFunc using the route's name if it has one. Otherwise, the
+// handler's package name and function name are used. For example, the handler
+// "myapp/http/controller/user.(*Controller).Update" gives the operationId "user.Update".
+// Static routes use their URI: "/public/{resource}" gives "static.public".
+// The method is appended to the operationId of HEAD and OPTIONS operations, e.g. "user.Index.head".
+func DefaultOperationID(route *goyave.Route, method, funcName string) string {
+	id := route.GetName()
+	if id == "" {
+		if isStaticRoute(route) {
+			id = staticOperationID(route)
+		} else {
+			id = cleanFuncName(funcName)
+		}
+	}
+	if method == http.MethodHead || method == http.MethodOptions {
+		id += "." + strings.ToLower(method)
+	}
+	return id
+}
+
+func cleanFuncName(funcName string) string {
+	name := strings.TrimSuffix(funcName[strings.LastIndex(funcName, "/")+1:], "-fm")
+	parts := strings.Split(name, ".")
+	if len(parts) > 2 && (strings.HasPrefix(parts[1], "(") || !closureSegmentFormat.MatchString(parts[2])) {
+		// Method, remove the receiver type
+		parts = append(parts[:1], parts[2:]...)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (c *RouteConverter) operationID(method string) string {
+	id := c.generator.operationIDFunc()(c.route, method, c.funcName)
+	if id == "" {
+		return ""
+	}
+
+	if c.generator.operationIDs == nil {
+		c.generator.operationIDs = make(map[string]struct{})
+	}
+	uniqueID := c.generator.uniqueOperationID(id)
+	c.generator.operationIDs[uniqueID] = struct{}{}
+	return uniqueID
+}
+
+func (g *generation) operationIDFunc() OperationIDFunc {
+	if g.OperationIDFunc == nil {
+		return DefaultOperationID
+	}
+	return g.OperationIDFunc
+}
+
+// uniqueOperationID returns the given operationId, with a number appended if it
+// is already used in the spec.
+func (g *generation) uniqueOperationID(id string) string {
+	uniqueID := id
+	for i := 2; ; i++ {
+		if _, exists := g.operationIDs[uniqueID]; !exists {
+			break
+		}
+		uniqueID = fmt.Sprintf("%s.%d", id, i)
+	}
+	return uniqueID
+}
@@ -0,0 +1,78 @@
+package openapi3
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+)
+
+type OperationTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *OperationTestSuite) TestCleanFuncName() {
+	suite.Equal("user.Update", cleanFuncName("myapp/http/controller/user.(*Controller).Update-fm"))
+	suite.Equal("user.Update", cleanFuncName("myapp/http/controller/user.Controller.Update-fm"))
+	suite.Equal("user.Update", cleanFuncName("myapp/http/controller/user.Update"))
+	suite.Equal("openapi3.TestReadDescriptionClosure.func1", cleanFuncName("goyave.dev/openapi3.(*RouteTestSuite).TestReadDescriptionClosure.func1"))
+	suite.Equal("route.Register.func1", cleanFuncName("myapp/http/route.Register.func1"))
+	suite.Equal("main.handler", cleanFuncName("main.handler"))
+}
+
+func (suite *OperationTestSuite) TestDefaultOperationID() {
+	router := goyave.NewRouter()
+	route := router.Get("/test", HandlerTest)
+	suite.Equal("openapi3.HandlerTest", DefaultOperationID(route, http.MethodGet, "goyave.dev/openapi3.HandlerTest"))
+
+	route.Name("test-route")
+	suite.Equal("test-route", DefaultOperationID(route, http.MethodGet, "goyave.dev/openapi3.HandlerTest"))
+	suite.Equal("test-route.head", DefaultOperationID(route, http.MethodHead, "goyave.dev/openapi3.HandlerTest"))
+	suite.Equal("test-route.options", DefaultOperationID(route, http.MethodOptions, "goyave.dev/openapi3.HandlerTest"))
+}
+
+func (suite *OperationTestSuite) TestGenerateOperationID() {
+	router := goyave.NewRouter()
+	router.Get("/first", HandlerTest)
+	router.Get("/second", HandlerTest)
+	router.Route("PUT|PATCH", "/named", HandlerTest).Name("named")
+
+	generator := NewGenerator()
+	spec := generator.Generate(router)
+	suite.Equal("openapi3.HandlerTest", spec.Paths["/first"].Get.OperationID)
+	suite.Equal("openapi3.HandlerTest.2", spec.Paths["/second"].Get.OperationID)
+	suite.Equal("named", spec.Paths["/named"].Put.OperationID)
+	suite.Equal("named.2", spec.Paths["/named"].Patch.OperationID)
+
+	// IDs are unique per spec
+	spec = generator.Generate(router)
+	suite.Equal("openapi3.HandlerTest", spec.Paths["/first"].Get.OperationID)
+}
+
+func (suite *OperationTestSuite) TestGenerateOperationIDFunc() {
+	router := goyave.NewRouter()
+	router.Route("GET|POST", "/test", HandlerTest)
+	router.Get("/empty", HandlerTest)
+
+	generator := NewGenerator()
+	generator.OperationIDFunc = func(route *goyave.Route, method, funcName string) string {
+		if route.GetURI() == "/empty" {
+			return ""
+		}
+		return strings.ToLower(method) + "Test"
+	}
+	spec := generator.Generate(router)
+	suite.Equal("getTest", spec.Paths["/test"].Get.OperationID)
+	suite.Equal("postTest", spec.Paths["/test"].Post.OperationID)
+	suite.Empty(spec.Paths["/empty"].Get.OperationID)
+}
+
+func TestOperationSuite(t *testing.T) {
+	if err := config.LoadJSON(`{"app":{"name": "Generator"}}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(OperationTestSuite))
+}
@@ -0,0 +1,625 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+)
+
+// Patch a transformation applied to the generated spec. Patches are applied
+// by the Generator after generation, in the order they were added.
+type Patch interface {
+	// Apply the patch to the given spec and return the result.
+	Apply(spec *openapi3.T) (*openapi3.T, error)
+}
+
+// Overlay OpenAPI Overlay 1.0 document. Each action targets nodes of the spec
+// using a JSONPath expression, and either updates or removes them.
+//
+// The following JSONPath syntax is supported:
+//   - root: "$"
+//   - child: ".name", "['name']", "[0]", "[-1]"
+//   - wildcard: ".*", "[*]"
+//   - recursive descent: "..name", "..*", "..[?filter]"
+//   - filter: "[?(@.name == 'value')]", "[?@.name != 1]", "[?@.name]" (existence)
+type Overlay struct {
+	Overlay string          `json:"overlay"`
+	Info    *OverlayInfo    `json:"info"`
+	Extends string          `json:"extends,omitempty"`
+	Actions []OverlayAction `json:"actions"`
+}
+
+// OverlayInfo metadata of an Overlay.
+type OverlayInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OverlayAction a single action of an Overlay.
+type OverlayAction struct {
+	// Target JSONPath expression selecting the nodes the action applies to.
+	Target string `json:"target"`
+
+	Description string `json:"description,omitempty"`
+
+	// Update value merged into the targeted objects, or appended to the targeted arrays.
+	Update interface{} `json:"update,omitempty"`
+
+	// Remove if true, the targeted nodes are removed.
+	Remove bool `json:"remove,omitempty"`
+}
+
+// LoadOverlay load the Overlay document (JSON or YAML) at the given path.
+func LoadOverlay(path string) (*Overlay, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	overlay := &Overlay{}
+	if err := yaml.Unmarshal(b, overlay); err != nil {
+		return nil, fmt.Errorf("openapi3: could not parse overlay %q: %w", path, err)
+	}
+	return overlay, nil
+}
+
+// Apply the actions of the overlay to the given spec, in order.
+// The given spec is not modified.
+func (o *Overlay) Apply(spec *openapi3.T) (*openapi3.T, error) {
+	doc, err := toTree(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, action := range o.Actions {
+		path, err := parseJSONPath(action.Target)
+		if err != nil {
+			return nil, fmt.Errorf("openapi3: overlay action %d: %w", i, err)
+		}
+		// The document only contains JSON types, but the update can be any Go value
+		update, err := normalizeTree(action.Update)
+		if err != nil {
+			return nil, fmt.Errorf("openapi3: overlay action %d: %w", i, err)
+		}
+		nodes := path.evaluate(doc)
+		for _, n := range nodes {
+			if action.Remove {
+				if n.set == nil {
+					return nil, fmt.Errorf("openapi3: overlay action %d: cannot remove the root node", i)
+				}
+				n.set(removedNode)
+				continue
+			}
+			n.update(cloneTree(update))
+		}
+		doc = sweepRemoved(doc)
+	}
+
+	return fromTree(doc)
+}
+
+// toTree converts the given spec to its generic JSON representation.
+func toTree(spec *openapi3.T) (interface{}, error) {
+	return normalizeTree(spec)
+}
+
+// normalizeTree converts the given value to its generic JSON representation,
+// made of maps, slices, strings, float64, booleans and nil.
+func normalizeTree(value interface{}) (interface{}, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// fromTree converts the given generic JSON representation to a spec. The refs are
+// not resolved, like in a generated spec, but an error is returned if a local ref
+// points to a node that doesn't exist.
+func fromTree(doc interface{}) (*openapi3.T, error) {
+	if err := checkRefs(doc, doc); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	spec := &openapi3.T{}
+	if err := json.Unmarshal(b, spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// checkRefs checks the local refs of the given value point to existing nodes of
+// the given document.
+func checkRefs(doc, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+			path, err := parsePointer(ref[1:])
+			if err == nil {
+				_, err = getValue(doc, path)
+			}
+			if err != nil {
+				return fmt.Errorf("openapi3: invalid ref %q: %w", ref, err)
+			}
+		}
+		for _, e := range v {
+			if err := checkRefs(doc, e); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, e := range v {
+			if err := checkRefs(doc, e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func cloneTree(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = cloneTree(e)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, e := range v {
+			a[i] = cloneTree(e)
+		}
+		return a
+	default:
+		return v
+	}
+}
+
+// removedNode marks nodes removed from their parent, until sweepRemoved is called.
+// This way, removing multiple elements of the same array doesn't shift the indexes.
+type removedMarker struct{}
+
+var removedNode = removedMarker{}
+
+func sweepRemoved(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if e == removedNode {
+				delete(v, k)
+				continue
+			}
+			v[k] = sweepRemoved(e)
+		}
+		return v
+	case []interface{}:
+		a := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			if e != removedNode {
+				a = append(a, sweepRemoved(e))
+			}
+		}
+		return a
+	default:
+		return v
+	}
+}
+
+// jsonNode a node selected by a JSONPath expression.
+type jsonNode struct {
+	value interface{}
+
+	// set replaces the node in its parent. Nil for the root node.
+	set func(value interface{})
+}
+
+func (n *jsonNode) update(update interface{}) {
+	switch target := n.value.(type) {
+	case map[string]interface{}:
+		if u, ok := update.(map[string]interface{}); ok {
+			mergeTree(target, u)
+			return
+		}
+	case []interface{}:
+		if n.set != nil {
+			n.set(append(target, update))
+			return
+		}
+	}
+	if n.set != nil {
+		n.set(update)
+	}
+}
+
+func mergeTree(target, update map[string]interface{}) {
+	for k, u := range update {
+		if t, ok := target[k].(map[string]interface{}); ok {
+			if um, ok := u.(map[string]interface{}); ok {
+				mergeTree(t, um)
+				continue
+			}
+		}
+		target[k] = u
+	}
+}
+
+func (n *jsonNode) children() []*jsonNode {
+	switch v := n.value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		children := make([]*jsonNode, 0, len(keys))
+		for _, k := range keys {
+			children = append(children, mapNode(v, k))
+		}
+		return children
+	case []interface{}:
+		children := make([]*jsonNode, 0, len(v))
+		for i := range v {
+			children = append(children, arrayNode(v, i))
+		}
+		return children
+	}
+	return nil
+}
+
+func (n *jsonNode) descendants() []*jsonNode {
+	nodes := []*jsonNode{n}
+	for _, c := range n.children() {
+		nodes = append(nodes, c.descendants()...)
+	}
+	return nodes
+}
+
+func mapNode(m map[string]interface{}, key string) *jsonNode {
+	return &jsonNode{value: m[key], set: func(value interface{}) { m[key] = value }}
+}
+
+func arrayNode(a []interface{}, index int) *jsonNode {
+	return &jsonNode{value: a[index], set: func(value interface{}) { a[index] = value }}
+}
+
+type jsonPathSelector struct {
+	recursive bool
+	wildcard  bool
+	names     []string
+	index     *int
+	filter    *jsonPathFilter
+}
+
+func (s *jsonPathSelector) selectNodes(n *jsonNode) []*jsonNode {
+	switch {
+	case s.wildcard:
+		return n.children()
+	case s.filter != nil:
+		nodes := []*jsonNode{}
+		for _, c := range n.children() {
+			if s.filter.match(c.value) {
+				nodes = append(nodes, c)
+			}
+		}
+		return nodes
+	case s.index != nil:
+		a, ok := n.value.([]interface{})
+		if !ok {
+			return nil
+		}
+		i := *s.index
+		if i < 0 {
+			i += len(a)
+		}
+		if i < 0 || i >= len(a) {
+			return nil
+		}
+		return []*jsonNode{arrayNode(a, i)}
+	default:
+		m, ok := n.value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		nodes := []*jsonNode{}
+		for _, name := range s.names {
+			if _, ok := m[name]; ok {
+				nodes = append(nodes, mapNode(m, name))
+			}
+		}
+		return nodes
+	}
+}
+
+type jsonPathFilter struct {
+	path     []string
+	operator string
+	value    interface{}
+}
+
+func (f *jsonPathFilter) match(value interface{}) bool {
+	for _, name := range f.path {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if value, ok = m[name]; !ok {
+			return false
+		}
+	}
+	switch f.operator {
+	case "==":
+		return reflect.DeepEqual(value, f.value)
+	case "!=":
+		return !reflect.DeepEqual(value, f.value)
+	default:
+		return true
+	}
+}
+
+type jsonPath []*jsonPathSelector
+
+func (p jsonPath) evaluate(doc interface{}) []*jsonNode {
+	nodes := []*jsonNode{{value: doc}}
+	for _, selector := range p {
+		selected := []*jsonNode{}
+		for _, n := range nodes {
+			candidates := []*jsonNode{n}
+			if selector.recursive {
+				candidates = n.descendants()
+			}
+			for _, c := range candidates {
+				selected = append(selected, selector.selectNodes(c)...)
+			}
+		}
+		nodes = selected
+	}
+	return nodes
+}
+
+func parseJSONPath(expr string) (jsonPath, error) {
+	p := &jsonPathParser{expr: expr}
+	path, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+	}
+	return path, nil
+}
+
+type jsonPathParser struct {
+	expr string
+	pos  int
+}
+
+func (p *jsonPathParser) parse() (jsonPath, error) {
+	if !strings.HasPrefix(p.expr, "$") {
+		return nil, fmt.Errorf("must start with \"$\"")
+	}
+	p.pos = 1
+	path := jsonPath{}
+	for p.pos < len(p.expr) {
+		selector := &jsonPathSelector{}
+		switch {
+		case strings.HasPrefix(p.expr[p.pos:], ".."):
+			selector.recursive = true
+			p.pos += 2
+			if p.pos < len(p.expr) && p.expr[p.pos] == '[' {
+				if err := p.parseBracket(selector); err != nil {
+					return nil, err
+				}
+				break
+			}
+			p.parseName(selector)
+		case p.expr[p.pos] == '.':
+			p.pos++
+			p.parseName(selector)
+		case p.expr[p.pos] == '[':
+			if err := p.parseBracket(selector); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", p.expr[p.pos], p.pos)
+		}
+		if !selector.wildcard && selector.names == nil && selector.index == nil && selector.filter == nil {
+			return nil, fmt.Errorf("empty selector at position %d", p.pos)
+		}
+		path = append(path, selector)
+	}
+	return path, nil
+}
+
+func (p *jsonPathParser) parseName(selector *jsonPathSelector) {
+	if p.pos < len(p.expr) && p.expr[p.pos] == '*' {
+		selector.wildcard = true
+		p.pos++
+		return
+	}
+	start := p.pos
+	for p.pos < len(p.expr) && p.expr[p.pos] != '.' && p.expr[p.pos] != '[' {
+		p.pos++
+	}
+	if p.pos > start {
+		selector.names = []string{p.expr[start:p.pos]}
+	}
+}
+
+func (p *jsonPathParser) parseBracket(selector *jsonPathSelector) error {
+	end := p.findClosingBracket()
+	if end == -1 {
+		return fmt.Errorf("unclosed bracket at position %d", p.pos)
+	}
+	content := strings.TrimSpace(p.expr[p.pos+1 : end])
+	p.pos = end + 1
+
+	switch {
+	case content == "*":
+		selector.wildcard = true
+	case strings.HasPrefix(content, "?"):
+		filter, err := parseJSONPathFilter(content[1:])
+		if err != nil {
+			return err
+		}
+		selector.filter = filter
+	case strings.HasPrefix(content, "'") || strings.HasPrefix(content, `"`):
+		for _, part := range splitUnquoted(content, ',') {
+			name, err := unquote(strings.TrimSpace(part))
+			if err != nil {
+				return err
+			}
+			selector.names = append(selector.names, name)
+		}
+	default:
+		index, err := strconv.Atoi(content)
+		if err != nil {
+			return fmt.Errorf("invalid index %q", content)
+		}
+		selector.index = &index
+	}
+	return nil
+}
+
+// findClosingBracket returns the position of the bracket closing the one at the
+// current position, ignoring the brackets in quoted strings.
+func (p *jsonPathParser) findClosingBracket() int {
+	depth := 0
+	var quote byte
+	for i := p.pos; i < len(p.expr); i++ {
+		c := p.expr[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseJSONPathFilter(expr string) (*jsonPathFilter, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+
+	filter := &jsonPathFilter{}
+	operand := expr
+	for _, operator := range []string{"==", "!="} {
+		if i := indexUnquoted(expr, operator); i != -1 {
+			filter.operator = operator
+			operand = strings.TrimSpace(expr[:i])
+			value, err := parseJSONPathLiteral(strings.TrimSpace(expr[i+len(operator):]))
+			if err != nil {
+				return nil, err
+			}
+			filter.value = value
+			break
+		}
+	}
+
+	if !strings.HasPrefix(operand, "@") {
+		return nil, fmt.Errorf("invalid filter %q: must start with \"@\"", expr)
+	}
+	relative, err := parseJSONPath("$" + operand[1:])
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range relative {
+		if s.recursive || s.wildcard || s.index != nil || s.filter != nil || len(s.names) != 1 {
+			return nil, fmt.Errorf("invalid filter %q: only child names are supported", expr)
+		}
+		filter.path = append(filter.path, s.names[0])
+	}
+	return filter, nil
+}
+
+func parseJSONPathLiteral(literal string) (interface{}, error) {
+	if strings.HasPrefix(literal, "'") || strings.HasPrefix(literal, `"`) {
+		return unquote(literal)
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(literal), &value); err != nil {
+		return nil, fmt.Errorf("invalid literal %q", literal)
+	}
+	return value, nil
+}
+
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != s[len(s)-1] || (s[0] != '\'' && s[0] != '"') {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	if s[0] == '\'' {
+		s = `"` + strings.ReplaceAll(strings.ReplaceAll(s[1:len(s)-1], `\'`, `'`), `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
+
+// indexUnquoted returns the index of the first occurrence of substr in s that is
+// not in a quoted string, or -1.
+func indexUnquoted(s, substr string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case strings.HasPrefix(s[i:], substr):
+			return i
+		}
+	}
+	return -1
+}
+
+// splitUnquoted splits the given string around the given separator, ignoring the
+// separators in quoted strings.
+func splitUnquoted(s string, separator byte) []string {
+	parts := []string{}
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == separator:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
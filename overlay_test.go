@@ -0,0 +1,176 @@
+package openapi3
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/suite"
+)
+
+type OverlayTestSuite struct {
+	suite.Suite
+}
+
+func makePatchTestSpec() *openapi3.T {
+	spec := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "Test", Version: "1.0.0"},
+		Paths:   openapi3.Paths{},
+		Servers: openapi3.Servers{{URL: "http://localhost"}, {URL: "http://staging"}},
+		Components: &openapi3.Components{
+			RequestBodies: openapi3.RequestBodies{
+				"user": &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithJSONSchema(openapi3.NewObjectSchema())},
+			},
+		},
+	}
+
+	admin := openapi3.NewOperation()
+	admin.Tags = []string{"admin"}
+	admin.AddResponse(0, openapi3.NewResponse().WithDescription(""))
+	spec.Paths["/admin/users"] = &openapi3.PathItem{Get: admin}
+
+	users := openapi3.NewOperation()
+	users.Tags = []string{"users"}
+	users.AddResponse(0, openapi3.NewResponse().WithDescription(""))
+	store := openapi3.NewOperation()
+	store.RequestBody = &openapi3.RequestBodyRef{Ref: "#/components/requestBodies/user"}
+	store.AddResponse(0, openapi3.NewResponse().WithDescription(""))
+	spec.Paths["/users"] = &openapi3.PathItem{Get: users, Post: store}
+	return spec
+}
+
+func (suite *OverlayTestSuite) TestApply() {
+	overlay := &Overlay{
+		Overlay: "1.0.0",
+		Info:    &OverlayInfo{Title: "Production", Version: "1.0.0"},
+		Actions: []OverlayAction{
+			{Target: "$.paths['/admin/users']", Remove: true},
+			{Target: "$.info", Update: map[string]interface{}{"title": "Production API", "x-env": "production"}},
+			{Target: "$.servers[?(@.url == 'http://staging')]", Remove: true},
+			{Target: "$.servers[0].url", Update: "https://api.example.com"},
+			{Target: "$.paths..[?@.tags]", Update: map[string]interface{}{"x-audit": true}},
+			{Target: "$.paths.*.get.tags", Update: "public"},
+		},
+	}
+
+	original := makePatchTestSpec()
+	spec, err := overlay.Apply(original)
+	suite.Require().NoError(err)
+
+	suite.NotContains(spec.Paths, "/admin/users")
+	suite.Equal("Production API", spec.Info.Title)
+	suite.Equal("1.0.0", spec.Info.Version)
+	suite.Equal("production", spec.Info.Extensions["x-env"])
+	suite.Len(spec.Servers, 1)
+	suite.Equal("https://api.example.com", spec.Servers[0].URL)
+
+	users := spec.Paths["/users"]
+	suite.Equal([]string{"users", "public"}, users.Get.Tags)
+	suite.Equal(true, users.Get.Extensions["x-audit"])
+	suite.NotContains(users.Post.Extensions, "x-audit")
+	// Refs are not resolved, like in a generated spec
+	suite.Equal("#/components/requestBodies/user", users.Post.RequestBody.Ref)
+	suite.Nil(users.Post.RequestBody.Value)
+
+	// The original spec is not modified
+	suite.Contains(original.Paths, "/admin/users")
+	suite.Equal("Test", original.Info.Title)
+}
+
+func (suite *OverlayTestSuite) TestApplyTypedUpdate() {
+	type contact struct {
+		Name string `json:"name"`
+	}
+	overlay := &Overlay{Actions: []OverlayAction{
+		{Target: "$.info", Update: map[string]string{"title": "Typed"}},
+		{Target: "$.info", Update: map[string]interface{}{"contact": contact{Name: "Goyave"}}},
+		{Target: "$", Update: map[string]map[string]int{"info": {"x-count": 3}}},
+		{Target: "$.servers", Update: map[string]string{"url": "http://production"}},
+	}}
+
+	spec, err := overlay.Apply(makePatchTestSpec())
+	suite.Require().NoError(err)
+	suite.Equal("Typed", spec.Info.Title)
+	suite.Equal("1.0.0", spec.Info.Version) // Merged, not replaced
+	suite.Require().NotNil(spec.Info.Contact)
+	suite.Equal("Goyave", spec.Info.Contact.Name)
+	suite.Contains(spec.Info.Extensions, "x-count")
+	suite.Len(spec.Servers, 3)
+	suite.Equal("http://production", spec.Servers[2].URL)
+
+	_, err = (&Overlay{Actions: []OverlayAction{{Target: "$.info", Update: make(chan int)}}}).Apply(makePatchTestSpec())
+	suite.Error(err)
+}
+
+func (suite *OverlayTestSuite) TestApplyErrors() {
+	_, err := (&Overlay{Actions: []OverlayAction{{Target: "$", Remove: true}}}).Apply(makePatchTestSpec())
+	suite.Error(err)
+
+	_, err = (&Overlay{Actions: []OverlayAction{{Target: "paths", Remove: true}}}).Apply(makePatchTestSpec())
+	suite.Error(err)
+
+	// Removing a component that is still referenced
+	_, err = (&Overlay{Actions: []OverlayAction{{Target: "$.components.requestBodies.user", Remove: true}}}).Apply(makePatchTestSpec())
+	suite.Error(err)
+}
+
+func (suite *OverlayTestSuite) TestLoadOverlay() {
+	path := filepath.Join(suite.T().TempDir(), "overlay.yaml")
+	content := `overlay: 1.0.0
+info:
+  title: Production
+  version: 1.0.0
+actions:
+  - target: "$.paths['/admin/users']"
+    description: Hide admin routes
+    remove: true
+  - target: $.info
+    update:
+      x-env: production
+`
+	suite.Require().NoError(os.WriteFile(path, []byte(content), 0644))
+	overlay, err := LoadOverlay(path)
+	suite.Require().NoError(err)
+	suite.Equal("1.0.0", overlay.Overlay)
+	suite.Equal("Production", overlay.Info.Title)
+	suite.Equal([]OverlayAction{
+		{Target: "$.paths['/admin/users']", Description: "Hide admin routes", Remove: true},
+		{Target: "$.info", Update: map[string]interface{}{"x-env": "production"}},
+	}, overlay.Actions)
+
+	_, err = LoadOverlay(filepath.Join(suite.T().TempDir(), "notafile.yaml"))
+	suite.Error(err)
+}
+
+func (suite *OverlayTestSuite) TestParseJSONPath() {
+	path, err := parseJSONPath("$..url")
+	suite.Require().NoError(err)
+	suite.Equal(jsonPath{{recursive: true, names: []string{"url"}}}, path)
+
+	path, err = parseJSONPath("$['a','b'][*][-1]")
+	suite.Require().NoError(err)
+	index := -1
+	suite.Equal(jsonPath{{names: []string{"a", "b"}}, {wildcard: true}, {index: &index}}, path)
+
+	path, err = parseJSONPath(`$.a[?(@.b.c == "x]")]`)
+	suite.Require().NoError(err)
+	suite.Equal(jsonPath{
+		{names: []string{"a"}},
+		{filter: &jsonPathFilter{path: []string{"b", "c"}, operator: "==", value: "x]"}},
+	}, path)
+
+	path, err = parseJSONPath(`$[?@.a != 1]`)
+	suite.Require().NoError(err)
+	suite.Equal(jsonPath{{filter: &jsonPathFilter{path: []string{"a"}, operator: "!=", value: float64(1)}}}, path)
+
+	for _, expr := range []string{"a", "$.a[", "$.[1]", "$[abc]", "$[?a]", "$[?@..a]", "$[?@.a == x]"} {
+		_, err = parseJSONPath(expr)
+		suite.Error(err, expr)
+	}
+}
+
+func TestOverlaySuite(t *testing.T) {
+	suite.Run(t, new(OverlayTestSuite))
+}
@@ -0,0 +1,114 @@
+package openapi3
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/validation"
+)
+
+// Plugin extends the Generator with hooks called at each stage of the generation.
+// Plugins can be used to implement conventions, such as adding vendor extensions
+// or headers to the operations.
+//
+// Embed BasePlugin in your plugin so you only need to implement the hooks you use.
+type Plugin interface {
+	// Init called once the spec is initialized, before the routes are converted.
+	Init(spec *openapi3.T)
+
+	// BeforeRoute called before the given route is converted.
+	BeforeRoute(route *goyave.Route, spec *openapi3.T)
+
+	// AfterRoute called after the given route is converted.
+	AfterRoute(route *goyave.Route, spec *openapi3.T)
+
+	// Operation called for each operation generated from the given route.
+	Operation(route *goyave.Route, method string, op *openapi3.Operation)
+
+	// Parameter called for each new path or query parameter. Parameters are
+	// components that can be shared by multiple routes: this hook is called with
+	// the first route using the parameter.
+	Parameter(route *goyave.Route, param *openapi3.Parameter)
+
+	// SchemaProperty called for each schema generated from a validation field (body fields,
+	// query parameters, array elements). Schemas generated from the same validation rules
+	// are shared by the routes using these rules: this hook is called once per field.
+	SchemaProperty(field *validation.Field, schema *openapi3.Schema)
+
+	// Finalize called once all the routes are converted, before the spec is merged
+	// into the generator's Base and patched.
+	Finalize(spec *openapi3.T)
+}
+
+// BasePlugin Plugin implementation with no-op hooks, meant to be embedded in your plugins.
+type BasePlugin struct{}
+
+// Init no-op hook.
+func (BasePlugin) Init(_ *openapi3.T) {}
+
+// BeforeRoute no-op hook.
+func (BasePlugin) BeforeRoute(_ *goyave.Route, _ *openapi3.T) {}
+
+// AfterRoute no-op hook.
+func (BasePlugin) AfterRoute(_ *goyave.Route, _ *openapi3.T) {}
+
+// Operation no-op hook.
+func (BasePlugin) Operation(_ *goyave.Route, _ string, _ *openapi3.Operation) {}
+
+// Parameter no-op hook.
+func (BasePlugin) Parameter(_ *goyave.Route, _ *openapi3.Parameter) {}
+
+// SchemaProperty no-op hook.
+func (BasePlugin) SchemaProperty(_ *validation.Field, _ *openapi3.Schema) {}
+
+// Finalize no-op hook.
+func (BasePlugin) Finalize(_ *openapi3.T) {}
+
+// AddPlugin register the given plugins. Their hooks are called in the order
+// the plugins were added.
+func (g *Generator) AddPlugin(plugins ...Plugin) {
+	g.plugins = append(g.plugins, plugins...)
+}
+
+type pluginList []Plugin
+
+func (p pluginList) init(spec *openapi3.T) {
+	for _, plugin := range p {
+		plugin.Init(spec)
+	}
+}
+
+func (p pluginList) beforeRoute(route *goyave.Route, spec *openapi3.T) {
+	for _, plugin := range p {
+		plugin.BeforeRoute(route, spec)
+	}
+}
+
+func (p pluginList) afterRoute(route *goyave.Route, spec *openapi3.T) {
+	for _, plugin := range p {
+		plugin.AfterRoute(route, spec)
+	}
+}
+
+func (p pluginList) operation(route *goyave.Route, method string, op *openapi3.Operation) {
+	for _, plugin := range p {
+		plugin.Operation(route, method, op)
+	}
+}
+
+func (p pluginList) parameter(route *goyave.Route, param *openapi3.Parameter) {
+	for _, plugin := range p {
+		plugin.Parameter(route, param)
+	}
+}
+
+func (p pluginList) schemaProperty(field *validation.Field, schema *openapi3.Schema) {
+	for _, plugin := range p {
+		plugin.SchemaProperty(field, schema)
+	}
+}
+
+func (p pluginList) finalize(spec *openapi3.T) {
+	for _, plugin := range p {
+		plugin.Finalize(spec)
+	}
+}
@@ -0,0 +1,106 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+	"goyave.dev/goyave/v4/validation"
+)
+
+type recordingPlugin struct {
+	BasePlugin
+	calls []string
+}
+
+func (p *recordingPlugin) Init(_ *openapi3.T) {
+	p.calls = append(p.calls, "init")
+}
+
+func (p *recordingPlugin) BeforeRoute(route *goyave.Route, _ *openapi3.T) {
+	p.calls = append(p.calls, "before "+route.GetFullURI())
+}
+
+func (p *recordingPlugin) AfterRoute(route *goyave.Route, _ *openapi3.T) {
+	p.calls = append(p.calls, "after "+route.GetFullURI())
+}
+
+func (p *recordingPlugin) Operation(_ *goyave.Route, method string, op *openapi3.Operation) {
+	p.calls = append(p.calls, "operation "+method)
+	op.Extensions = map[string]interface{}{"x-plugin": true}
+}
+
+func (p *recordingPlugin) Parameter(_ *goyave.Route, param *openapi3.Parameter) {
+	p.calls = append(p.calls, "parameter "+param.In+" "+param.Name)
+}
+
+func (p *recordingPlugin) SchemaProperty(_ *validation.Field, schema *openapi3.Schema) {
+	p.calls = append(p.calls, "schema "+schema.Type)
+}
+
+func (p *recordingPlugin) Finalize(spec *openapi3.T) {
+	p.calls = append(p.calls, "finalize")
+	spec.Info.Description = "Finalized"
+}
+
+type PluginTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *PluginTestSuite) TestHooks() {
+	router := goyave.NewRouter()
+	router.Get("/users/{id:[0-9]+}", HandlerTest).Validate(&validation.Rules{
+		Fields: validation.FieldMap{
+			"fields": &validation.Field{Rules: []*validation.Rule{{Name: "string"}}},
+		},
+	})
+
+	plugin := &recordingPlugin{}
+	second := &recordingPlugin{}
+	generator := NewGenerator()
+	generator.AddPlugin(plugin, second)
+	spec := generator.Generate(router)
+
+	expected := []string{
+		"init",
+		"before /users/{id:[0-9]+}",
+		"schema string",
+		"parameter query fields",
+		"parameter header Accept-Language",
+		"operation GET",
+		"parameter path id",
+		"after /users/{id:[0-9]+}",
+		"finalize",
+	}
+	suite.Equal(expected, plugin.calls)
+	suite.Equal(expected, second.calls)
+	suite.Equal(true, spec.Paths["/users/{id}"].Get.Extensions["x-plugin"])
+	suite.Equal("Finalized", spec.Info.Description)
+}
+
+func (suite *PluginTestSuite) TestBasePlugin() {
+	router := goyave.NewRouter()
+	router.Get("/users", HandlerTest)
+
+	generator := NewGenerator()
+	generator.AddPlugin(BasePlugin{})
+	suite.NotNil(generator.Generate(router))
+}
+
+func TestPluginSuite(t *testing.T) {
+	if err := config.LoadJSON(`{
+			"app": {
+				"name": "Generator"
+			},
+			"server": {
+				"protocol": "http",
+				"domain": "goyave.dev",
+				"port": 80
+			}
+		}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(PluginTestSuite))
+}
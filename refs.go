@@ -2,6 +2,8 @@ package openapi3
 
 import (
 	"go/ast"
+	"go/token"
+	"sync"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"goyave.dev/goyave/v4/validation"
@@ -9,29 +11,112 @@ import (
 
 // Refs cache structure associating validation rules pointers to OpenAPI refs
 // to avoid generating them multiple times and allow the use of OpenAPI components.
+//
+// Refs are specific to the spec they are generated for and must not be shared by
+// multiple specs. The source analysis results are stored in the embedded Cache,
+// which can be shared.
 type Refs struct {
-	Schemas         map[*validation.Rules]*openapi3.SchemaRef
-	ParamSchemas    map[string]*openapi3.SchemaRef
-	Parameters      map[string]*openapi3.ParameterRef
-	QueryParameters map[*validation.Rules][]*openapi3.ParameterRef
-	RequestBodies   map[*validation.Rules]*openapi3.RequestBodyRef
-	AST             map[string]*ast.File
-	HandlerDocs     map[uintptr]*HandlerDoc
+	Schemas              map[*validation.Rules]*openapi3.SchemaRef
+	ParamSchemas         map[string]*openapi3.SchemaRef
+	Parameters           map[string]*openapi3.ParameterRef
+	QueryParameters      map[*validation.Rules][]*openapi3.ParameterRef
+	RequestBodies        map[*validation.Rules]*openapi3.RequestBodyRef
+	ValidationErrors     map[*validation.Rules]*openapi3.SchemaRef
+	MiddlewareParameters map[*openapi3.ParameterRef]*openapi3.ParameterRef
+	*Cache
 }
 
-// NewRefs create a new Refs struct with initialized maps.
+// NewRefs create a new Refs struct with initialized maps and a new Cache.
 func NewRefs() *Refs {
+	return newRefs(NewCache())
+}
+
+func newRefs(cache *Cache) *Refs {
 	return &Refs{
-		Schemas:         make(map[*validation.Rules]*openapi3.SchemaRef),
-		ParamSchemas:    make(map[string]*openapi3.SchemaRef),
-		Parameters:      make(map[string]*openapi3.ParameterRef),
-		QueryParameters: make(map[*validation.Rules][]*openapi3.ParameterRef),
-		RequestBodies:   make(map[*validation.Rules]*openapi3.RequestBodyRef),
-		AST:             make(map[string]*ast.File),
-		HandlerDocs:     make(map[uintptr]*HandlerDoc),
+		Schemas:              make(map[*validation.Rules]*openapi3.SchemaRef),
+		ParamSchemas:         make(map[string]*openapi3.SchemaRef),
+		Parameters:           make(map[string]*openapi3.ParameterRef),
+		QueryParameters:      make(map[*validation.Rules][]*openapi3.ParameterRef),
+		RequestBodies:        make(map[*validation.Rules]*openapi3.RequestBodyRef),
+		ValidationErrors:     make(map[*validation.Rules]*openapi3.SchemaRef),
+		MiddlewareParameters: make(map[*openapi3.ParameterRef]*openapi3.ParameterRef),
+		Cache:                cache,
 	}
 }
 
+// Cache the results of the source code analysis (files, handlers and packages
+// documentation), which don't depend on the generated spec.
+//
+// A Cache is safe for concurrent use and can be shared by multiple generators.
+// Its maps must not be accessed directly while a spec is being generated.
+type Cache struct {
+	// AST is not used anymore: only the documentation of the parsed files is kept, in Files.
+	//
+	// Deprecated: always empty, use Files instead. Will be removed in the next major version.
+	AST map[string]*ast.File
+
+	// FileSet is not used anymore: the parsed files are released once their
+	// documentation has been extracted.
+	//
+	// Deprecated: always empty. Will be removed in the next major version.
+	FileSet *token.FileSet
+
+	Files       map[string]*FileDoc
+	HandlerDocs map[uintptr]*HandlerDoc
+	PackageDocs map[string]string
+	mu          sync.RWMutex
+}
+
+// NewCache create a new Cache with initialized maps.
+func NewCache() *Cache {
+	return &Cache{
+		AST:         make(map[string]*ast.File),
+		FileSet:     token.NewFileSet(),
+		Files:       make(map[string]*FileDoc),
+		HandlerDocs: make(map[uintptr]*HandlerDoc),
+		PackageDocs: make(map[string]string),
+	}
+}
+
+func (c *Cache) getFileDoc(file string) (*FileDoc, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	doc, ok := c.Files[file]
+	return doc, ok
+}
+
+func (c *Cache) setFileDoc(file string, doc *FileDoc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Files[file] = doc
+}
+
+func (c *Cache) getHandlerDoc(pc uintptr) (*HandlerDoc, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	doc, ok := c.HandlerDocs[pc]
+	return doc, ok
+}
+
+func (c *Cache) setHandlerDoc(pc uintptr, doc *HandlerDoc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.HandlerDocs[pc] = doc
+}
+
+func (c *Cache) getPackageDoc(dir string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	doc, ok := c.PackageDocs[dir]
+	return doc, ok
+}
+
+func (c *Cache) setPackageDoc(dir, doc string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.PackageDocs[dir] = doc
+}
+
 // HandlerDoc info extracted from AST about a Handler.
 type HandlerDoc struct {
 	FuncName    string
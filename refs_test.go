@@ -14,6 +14,21 @@ func TestNewRefs(t *testing.T) {
 	assert.NotNil(t, refs.Parameters)
 	assert.NotNil(t, refs.QueryParameters)
 	assert.NotNil(t, refs.RequestBodies)
-	assert.NotNil(t, refs.AST)
+	assert.NotNil(t, refs.ValidationErrors)
+	assert.NotNil(t, refs.Files)
 	assert.NotNil(t, refs.HandlerDocs)
+	assert.NotNil(t, refs.PackageDocs)
+}
+
+func TestNewCache(t *testing.T) {
+	cache := NewCache()
+	assert.NotNil(t, cache)
+	assert.NotNil(t, cache.Files)
+	assert.NotNil(t, cache.HandlerDocs)
+	assert.NotNil(t, cache.PackageDocs)
+
+	cache.setPackageDoc("dir", "docs")
+	doc, ok := cache.getPackageDoc("dir")
+	assert.True(t, ok)
+	assert.Equal(t, "docs", doc)
 }
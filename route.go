@@ -2,11 +2,7 @@ package openapi3
 
 import (
 	"fmt"
-	"go/ast"
-	"go/parser"
-	"go/token"
 	"net/http"
-	"os"
 	"reflect"
 	"regexp"
 	"runtime"
@@ -21,13 +17,14 @@ import (
 var (
 	urlParamFormat        = regexp.MustCompile(`{\w+(:.+?)?}`)
 	refInvalidCharsFormat = regexp.MustCompile(`[^A-Za-z0-9-._]`)
-	closureFormat         = regexp.MustCompile(`\.[a-zA-Z-]+\.func[0-9]+$`)
+	closureFormat         = regexp.MustCompile(`\.[a-zA-Z-]*\.func[0-9]+(\.[0-9]+)*$`)
 )
 
 // RouteConverter converts goyave.Route to OpenAPI operations.
 type RouteConverter struct {
 	route       *goyave.Route
 	refs        *Refs
+	generator   *generation
 	uri         string
 	tag         string
 	description string
@@ -38,19 +35,30 @@ type RouteConverter struct {
 // The converter will use and fill the given Refs.
 func NewRouteConverter(route *goyave.Route, refs *Refs) *RouteConverter {
 	return &RouteConverter{
-		route: route,
-		refs:  refs,
+		route:     route,
+		refs:      refs,
+		generator: &generation{Generator: &Generator{}},
 	}
 }
 
 // Convert route to OpenAPI operations and adds the results to the given spec.
 func (c *RouteConverter) Convert(spec *openapi3.T) {
 	c.uri = c.cleanPath(c.route)
-	c.tag = c.uriToTag(c.uri)
-	c.funcName, c.description = c.readDescription()
+	c.tag = c.convertTag()
+	static := isStaticRoute(c.route)
+	if static {
+		// The static handler is a closure from goyave: its source is not read.
+		pc := reflect.ValueOf(c.route.GetHandler()).Pointer()
+		c.funcName, c.description = runtime.FuncForPC(pc).Name(), staticDescription
+	} else {
+		c.funcName, c.description = c.readDescription()
+	}
+	if c.tag != "" {
+		c.addTag(spec)
+	}
 
 	for _, m := range c.route.GetMethods() {
-		if m == http.MethodHead || m == http.MethodOptions {
+		if (m == http.MethodHead || m == http.MethodOptions) && !c.generator.IncludeHeadAndOptions {
 			continue
 		}
 		if !c.operationExists(spec, c.uri, m) {
@@ -59,6 +67,9 @@ func (c *RouteConverter) Convert(spec *openapi3.T) {
 	}
 
 	c.convertPathParameters(spec.Paths[c.uri], spec)
+	if static {
+		describeStaticParameter(spec.Paths[c.uri], spec)
+	}
 }
 
 func (c *RouteConverter) operationExists(spec *openapi3.T, path, method string) bool {
@@ -99,19 +110,53 @@ func (c *RouteConverter) convertOperation(method string, spec *openapi3.T) *open
 	if c.tag != "" {
 		op.Tags = []string{c.tag}
 	}
-	op.Description = c.description
-
-	c.convertValidationRules(method, op, spec)
+	op.Summary, op.Description = convertDoc(c.description)
+	op.OperationID = c.operationID(method)
+
+	options := corsOptions(c.route.GetParent())
+	// Preflight requests are handled by the CORS middleware: the rules are not checked
+	preflight := options != nil && isPreflight(method, options)
+	if !preflight {
+		c.convertValidationRules(method, op, spec)
+	}
 
 	op.Responses = openapi3.Responses{}
 	// TODO annotations or something else for responses
 	if len(op.Responses) == 0 {
 		op.Responses["default"] = &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("")}
 	}
+	if rules := c.route.GetValidationRules(); rules != nil && !preflight {
+		op.Responses["422"] = c.convertValidationError(rules, spec)
+	}
+	if isStaticRoute(c.route) {
+		convertStaticResponses(op)
+	}
+	if options != nil {
+		convertCORS(method, op, options)
+	}
+	c.convertMiddleware(op, spec)
+	if method == http.MethodHead {
+		removeResponseBodies(op)
+	}
+	c.generator.plugins.operation(c.route, method, op)
 	return op
 }
 
+// removeResponseBodies removes the content of the responses of the given operation.
+// The responses to HEAD requests don't have a body.
+func removeResponseBodies(op *openapi3.Operation) {
+	for _, response := range op.Responses {
+		if response.Value != nil {
+			response.Value.Content = nil
+		}
+	}
+}
+
 func (c *RouteConverter) cleanPath(route *goyave.Route) string {
+	return cleanPath(route)
+}
+
+func cleanPath(route *goyave.Route) string {
 	// Regex are not allowed in URI, generate it without format definition
 	_, params := route.GetFullURIAndParameters()
 	bracedParams := make([]string, 0, len(params))
@@ -124,22 +169,25 @@ func (c *RouteConverter) cleanPath(route *goyave.Route) string {
 
 func (c *RouteConverter) uriToTag(uri string) string {
 	// Take the first segment of the uri and use it as tag
-	tag := ""
+	return TagFirstSegment(c.route, uri)
+}
+
+func firstSegment(uri string) string {
+	if uri == "" {
+		return ""
+	}
 	startIndex := 1
 	if uri[0] != '/' {
 		startIndex = 0
 	}
 	if i := strings.Index(uri[startIndex:], "/"); i != -1 {
-		tag = uri[startIndex : i+startIndex]
-	} else {
-		tag = uri[startIndex:]
-	}
-	if len(tag) > 2 && tag[0] == '{' && tag[len(tag)-1] == '}' {
-		// The first segment is a parameter
-		return ""
+		return uri[startIndex : i+startIndex]
 	}
+	return uri[startIndex:]
+}
 
-	return tag
+func isParameter(segment string) bool {
+	return len(segment) > 2 && segment[0] == '{' && segment[len(segment)-1] == '}'
 }
 
 func (c *RouteConverter) convertPathParameters(path *openapi3.PathItem, spec *openapi3.T) {
@@ -172,6 +220,7 @@ func (c *RouteConverter) convertPathParameters(path *openapi3.PathItem, spec *op
 			}
 			param := openapi3.NewPathParameter(p)
 			param.Schema = schemaRef
+			c.generator.plugins.parameter(c.route, param)
 			spec.Components.Parameters[paramName] = &openapi3.ParameterRef{Value: param}
 			paramRef := &openapi3.ParameterRef{Ref: "#/components/parameters/" + paramName}
 			c.refs.Parameters[paramName] = paramRef
@@ -228,7 +277,7 @@ func (c *RouteConverter) convertValidationRules(method string, op *openapi3.Oper
 				op.RequestBody = cached
 				return
 			}
-			requestBody := ConvertToBody(rules)
+			requestBody := convertToBody(rules, c.generator.plugins)
 			refName := c.rulesRefName()
 			spec.Components.RequestBodies[refName] = requestBody
 			requestBodyRef := &openapi3.RequestBodyRef{Ref: "#/components/requestBodies/" + refName}
@@ -240,9 +289,10 @@ func (c *RouteConverter) convertValidationRules(method string, op *openapi3.Oper
 				return
 			}
 			refName := c.rulesRefName() + "-query-"
-			query := ConvertToQuery(rules)
+			query := convertToQuery(rules, c.generator.plugins)
 			c.refs.QueryParameters[rules] = make([]*openapi3.ParameterRef, 0, len(query))
 			for _, p := range query {
+				c.generator.plugins.parameter(c.route, p.Value)
 				paramRefName := refName + p.Value.Name
 				spec.Components.Parameters[paramRefName] = p
 
@@ -262,95 +312,44 @@ func (c *RouteConverter) rulesRefName() string {
 
 func (c *RouteConverter) readDescription() (string, string) {
 	pc := reflect.ValueOf(c.route.GetHandler()).Pointer()
-	if cached, ok := c.refs.HandlerDocs[pc]; ok {
+	if cached, ok := c.refs.getHandlerDoc(pc); ok {
 		return cached.FuncName, cached.Description
 	}
 	handlerValue := runtime.FuncForPC(pc)
 	funcName := handlerValue.Name()
 
-	if closureFormat.MatchString(funcName) {
-		// Closures can't be documented, there's no need to parse AST
-		c.refs.HandlerDocs[pc] = &HandlerDoc{funcName, ""}
-		return funcName, ""
-	}
-
-	file, _ := handlerValue.FileLine(pc)
+	file, line := handlerValue.FileLine(pc)
 	if file == "<autogenerated>" {
 		// Fix for Go 1.18 change
 		// https://github.com/golang/go/issues/51774
 
 		return funcName, ""
 	}
-	astFile := c.getAST(file)
-
-	var doc *ast.CommentGroup
-
-	ast.Inspect(astFile, func(n ast.Node) bool {
-		// Example output of "funcName" value for controller: goyave.dev/goyave/v4/auth.(*JWTController).Login-fm
-		fn, ok := n.(*ast.FuncDecl)
-		if ok {
-			if fn.Recv != nil {
-				for _, f := range fn.Recv.List {
-					strct := ""
-					switch expr := f.Type.(type) {
-					case *ast.StarExpr:
-						if id, ok := expr.X.(*ast.Ident); ok {
-							strct = fmt.Sprintf("(*%s)", id.Name)
-						} else {
-							continue
-						}
-					case *ast.Ident:
-						strct = expr.Name
-					default:
-						continue
-					}
-					name := funcName
-					if strings.HasSuffix(name, "-fm") {
-						// strip -fm suffix
-						name = funcName[:len(funcName)-3]
-					}
-					expectedName := strct + "." + fn.Name.Name
-					startIndex := len(name) - len(expectedName)
-					if startIndex > 0 && name[startIndex:] == expectedName {
-						doc = fn.Doc
-						return false
-					}
-				}
-				return true
-			}
-			lastIndex := strings.LastIndex(funcName, ".")
-			if funcName[lastIndex+1:] == fn.Name.Name {
-				doc = fn.Doc
-				return false
-			}
-		}
-		return true
-	})
+	fileDoc := c.getFileDoc(file)
 
-	docs := ""
-	if doc != nil {
-		docs = strings.TrimSpace(doc.Text())
+	var docs string
+	if closureFormat.MatchString(funcName) {
+		docs = fileDoc.Closures[line]
+	} else {
+		docs = fileDoc.FuncDoc(funcName)
 	}
 
-	c.refs.HandlerDocs[pc] = &HandlerDoc{funcName, docs}
+	c.refs.setHandlerDoc(pc, &HandlerDoc{funcName, docs})
 	return funcName, docs
 }
 
-func (c *RouteConverter) getAST(file string) *ast.File {
-	astFile := c.refs.AST[file]
-	if astFile == nil {
-		src, err := os.ReadFile(file)
-		if err != nil {
-			panic(err)
-		}
-
-		fset := token.NewFileSet() // positions are relative to fset
-
-		astFile, err = parser.ParseFile(fset, file, src, parser.ParseComments)
+// getFileDoc returns the documentation of the given file, parsing it if it is not
+// in the cache yet. Generate parses the files of all the handlers beforehand and returns
+// the parse errors, so this only panics if the RouteConverter is used on its own.
+func (c *RouteConverter) getFileDoc(file string) *FileDoc {
+	fileDoc, _ := c.refs.getFileDoc(file)
+	if fileDoc == nil {
+		var err error
+		fileDoc, err = parseFileDoc(file)
 		if err != nil {
 			panic(err)
 		}
-		c.refs.AST[file] = astFile
+		c.refs.setFileDoc(file, fileDoc)
 	}
-	return astFile
+	return fileDoc
 }
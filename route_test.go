@@ -1,7 +1,6 @@
 package openapi3
 
 import (
-	"go/ast"
 	"net/http"
 	"reflect"
 	"runtime"
@@ -90,28 +89,28 @@ func (suite *RouteTestSuite) TestRulesRefName() {
 	suite.Equal("auth.JWTController.Login-fm", converter.rulesRefName())
 }
 
-func (suite *RouteTestSuite) TestGetAST() {
+func (suite *RouteTestSuite) TestGetFileDoc() {
 	refs := NewRefs()
 	converter := NewRouteConverter(&goyave.Route{}, refs)
-	ast := converter.getAST("route.go")
-	suite.Contains(refs.AST, "route.go")
-	suite.Same(refs.AST["route.go"], ast)
+	fileDoc := converter.getFileDoc("route.go")
+	suite.Contains(refs.Files, "route.go")
+	suite.Same(refs.Files["route.go"], fileDoc)
 
 	suite.Panics(func() {
-		converter.getAST("notafile")
+		converter.getFileDoc("notafile")
 	})
 	suite.Panics(func() {
 		// Not a go file
-		converter.getAST("go.mod")
+		converter.getFileDoc("go.mod")
 	})
 }
 
-func (suite *RouteTestSuite) TestGetASTCached() {
+func (suite *RouteTestSuite) TestGetFileDocCached() {
 	refs := NewRefs()
-	astFile := &ast.File{}
-	refs.AST["route.go"] = astFile
+	fileDoc := &FileDoc{}
+	refs.Files["route.go"] = fileDoc
 	converter := NewRouteConverter(&goyave.Route{}, refs)
-	suite.Same(astFile, converter.getAST("route.go"))
+	suite.Same(fileDoc, converter.getFileDoc("route.go"))
 }
 
 func (suite *RouteTestSuite) TestReadDescription() {
@@ -141,6 +140,42 @@ func (suite *RouteTestSuite) TestReadDescriptionClosure() {
 	suite.Contains(refs.HandlerDocs, pc)
 }
 
+func (suite *RouteTestSuite) TestReadDescriptionDocumentedClosure() {
+	refs := NewRefs()
+	router := goyave.NewRouter()
+
+	// Documented closure handler.
+	//
+	// More details.
+	route := router.Get("/test", func(r1 *goyave.Response, r2 *goyave.Request) {
+		r1.Status(http.StatusOK)
+	})
+	converter := NewRouteConverter(route, refs)
+
+	funcName, description := converter.readDescription()
+	suite.Equal("goyave.dev/openapi3.(*RouteTestSuite).TestReadDescriptionDocumentedClosure.func1", funcName)
+	suite.Equal("Documented closure handler.\n\nMore details.", description)
+
+	_ = route // Trailing comment of the previous statement
+	route = router.Get("/trailing", func(r1 *goyave.Response, r2 *goyave.Request) {})
+	converter = NewRouteConverter(route, refs)
+	_, description = converter.readDescription()
+	suite.Empty(description)
+
+	route = router.Get("/global", documentedClosure)
+	converter = NewRouteConverter(route, refs)
+	funcName, description = converter.readDescription()
+	// The name depends on the Go version: "glob..func1" or "init.func1"
+	suite.True(strings.HasPrefix(funcName, "goyave.dev/openapi3."), funcName)
+	suite.Regexp(closureFormat, funcName)
+	suite.Equal("documentedClosure a closure handler declared at package level", description)
+}
+
+// documentedClosure a closure handler declared at package level
+var documentedClosure = func(resp *goyave.Response, _ *goyave.Request) {
+	resp.Status(http.StatusOK)
+}
+
 func (suite *RouteTestSuite) TestReadDescriptionStruct() {
 	version := runtime.Version()
 	refs := NewRefs()
@@ -436,12 +471,16 @@ func (suite *RouteTestSuite) TestConvertOperation() {
 	converter := NewRouteConverter(route, refs)
 	converter.funcName = "HandlerTest"
 	converter.tag = "TestTag"
-	converter.description = "Test Description"
+	converter.description = "Test summary. Test Description\n\nMore details"
 
 	op := converter.convertOperation(http.MethodPost, spec)
 	suite.Equal(converter.tag, op.Tags[0])
-	suite.Equal(converter.description, op.Description)
+	suite.Equal("Test summary.", op.Summary)
+	suite.Equal("Test Description\n\nMore details", op.Description)
+	suite.Equal("HandlerTest", op.OperationID)
 	suite.Contains(op.Responses, "default")
+	suite.Contains(op.Responses, "422")
+	suite.Contains(spec.Components.Schemas, "HandlerTest-validationError")
 	suite.NotNil(op.RequestBody)
 }
 
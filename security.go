@@ -0,0 +1,57 @@
+package openapi3
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+type securityScheme struct {
+	scheme *openapi3.SecurityScheme
+	name   string
+	scopes []string
+}
+
+// AddOAuth2 declare an OAuth2 security scheme identified by the given name and
+// using the given flows. The scheme is added to the components of the generated
+// specs and required on every operation with the given scopes.
+//
+// The scopes available for each flow are defined in the flows' "Scopes" field.
+func (g *Generator) AddOAuth2(name string, flows *openapi3.OAuthFlows, scopes ...string) {
+	g.AddSecurityScheme(name, NewOAuth2SecurityScheme(flows), scopes...)
+}
+
+// AddOpenIDConnect declare an OpenID Connect security scheme identified by the given name
+// and using the given discovery URL. The scheme is added to the components of the
+// generated specs and required on every operation with the given scopes.
+func (g *Generator) AddOpenIDConnect(name, url string, scopes ...string) {
+	g.AddSecurityScheme(name, openapi3.NewOIDCSecurityScheme(url), scopes...)
+}
+
+// AddSecurityScheme declare a security scheme identified by the given name. The scheme
+// is added to the components of the generated specs and required on every operation with
+// the given scopes.
+func (g *Generator) AddSecurityScheme(name string, scheme *openapi3.SecurityScheme, scopes ...string) {
+	g.securitySchemes = append(g.securitySchemes, &securityScheme{
+		scheme: scheme,
+		name:   name,
+		scopes: scopes,
+	})
+}
+
+// NewOAuth2SecurityScheme create a new OAuth2 security scheme using the given flows.
+func NewOAuth2SecurityScheme(flows *openapi3.OAuthFlows) *openapi3.SecurityScheme {
+	return &openapi3.SecurityScheme{
+		Type:  "oauth2",
+		Flows: flows,
+	}
+}
+
+func (g *generation) convertSecuritySchemes() {
+	if len(g.securitySchemes) == 0 {
+		return
+	}
+	g.spec.Components.SecuritySchemes = make(openapi3.SecuritySchemes, len(g.securitySchemes))
+	for _, s := range g.securitySchemes {
+		g.spec.Components.SecuritySchemes[s.name] = &openapi3.SecuritySchemeRef{Value: s.scheme}
+		g.spec.Security.With(openapi3.NewSecurityRequirement().Authenticate(s.name, s.scopes...))
+	}
+}
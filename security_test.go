@@ -0,0 +1,61 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+)
+
+type SecurityTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *SecurityTestSuite) TestAddOAuth2() {
+	generator := NewGenerator()
+	flows := &openapi3.OAuthFlows{
+		AuthorizationCode: &openapi3.OAuthFlow{
+			AuthorizationURL: "https://auth.example.org/authorize",
+			TokenURL:         "https://auth.example.org/token",
+			Scopes: map[string]string{
+				"read":  "Read access",
+				"write": "Write access",
+			},
+		},
+	}
+	generator.AddOAuth2("oauth", flows, "read")
+
+	spec := generator.Generate(goyave.NewRouter())
+	suite.Contains(spec.Components.SecuritySchemes, "oauth")
+	scheme := spec.Components.SecuritySchemes["oauth"].Value
+	suite.Equal("oauth2", scheme.Type)
+	suite.Same(flows, scheme.Flows)
+	suite.Equal(openapi3.SecurityRequirements{{"oauth": {"read"}}}, spec.Security)
+}
+
+func (suite *SecurityTestSuite) TestAddOpenIDConnect() {
+	generator := NewGenerator()
+	generator.AddOpenIDConnect("oidc", "https://auth.example.org/.well-known/openid-configuration")
+
+	spec := generator.Generate(goyave.NewRouter())
+	suite.Contains(spec.Components.SecuritySchemes, "oidc")
+	scheme := spec.Components.SecuritySchemes["oidc"].Value
+	suite.Equal("openIdConnect", scheme.Type)
+	suite.Equal("https://auth.example.org/.well-known/openid-configuration", scheme.OpenIdConnectUrl)
+	suite.Equal(openapi3.SecurityRequirements{{"oidc": {}}}, spec.Security)
+}
+
+func (suite *SecurityTestSuite) TestNoSecurityScheme() {
+	spec := NewGenerator().Generate(goyave.NewRouter())
+	suite.Nil(spec.Components.SecuritySchemes)
+	suite.Nil(spec.Security)
+}
+
+func TestSecuritySuite(t *testing.T) {
+	if err := config.LoadJSON(`{"app":{"name": "Generator"}}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(SecurityTestSuite))
+}
@@ -0,0 +1,216 @@
+package openapi3
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"goyave.dev/goyave/v4"
+)
+
+// FileDoc documentation extracted from a Go source file. Only the doc comments
+// are kept so the parsed files can be released.
+type FileDoc struct {
+	// Funcs doc comments of the function and method declarations, indexed by name.
+	// Methods are prefixed with their receiver type, e.g. "(*UserController).Index".
+	Funcs map[string]string
+
+	// Closures doc comments of the function literals, indexed by the line they start at.
+	// The doc comment of a function literal is the comment immediately preceding the
+	// statement or declaration containing it (usually the route registration).
+	Closures map[int]string
+}
+
+// FuncDoc returns the doc comment of the function or method with the given name,
+// as returned by runtime.FuncForPC.
+func (d *FileDoc) FuncDoc(funcName string) string {
+	name := strings.TrimSuffix(funcName[strings.LastIndex(funcName, "/")+1:], "-fm")
+	parts := strings.Split(name, ".")
+	if len(parts) > 2 {
+		if doc, ok := d.Funcs[parts[len(parts)-2]+"."+parts[len(parts)-1]]; ok {
+			return doc
+		}
+	}
+	return d.Funcs[parts[len(parts)-1]]
+}
+
+// parseFileDoc parses the given Go source file and extracts its doc comments.
+func parseFileDoc(file string) (*FileDoc, error) {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	astFile, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &FileDoc{
+		Funcs:    make(map[string]string),
+		Closures: make(map[int]string),
+	}
+	comments := make(map[int][]*ast.CommentGroup, len(astFile.Comments))
+	for _, cg := range astFile.Comments {
+		line := fset.Position(cg.End()).Line
+		comments[line] = append(comments[line], cg)
+	}
+
+	stack := make([]ast.Node, 0, 16)
+	ast.Inspect(astFile, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			if node.Doc != nil {
+				doc.Funcs[funcDeclName(node)] = strings.TrimSpace(node.Doc.Text())
+			}
+		case *ast.FuncLit:
+			line := fset.Position(node.Pos()).Line
+			if _, ok := doc.Closures[line]; !ok {
+				doc.Closures[line] = closureDoc(fset, stack, comments)
+			}
+		}
+		stack = append(stack, n)
+		return true
+	})
+
+	for line, d := range doc.Closures {
+		if d == "" {
+			delete(doc.Closures, line)
+		}
+	}
+	return doc, nil
+}
+
+// funcDeclName returns the name of the given function declaration, prefixed
+// with its receiver type if it is a method.
+func funcDeclName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	switch expr := fn.Recv.List[0].Type.(type) {
+	case *ast.StarExpr:
+		if id, ok := expr.X.(*ast.Ident); ok {
+			return fmt.Sprintf("(*%s).%s", id.Name, fn.Name.Name)
+		}
+	case *ast.Ident:
+		return expr.Name + "." + fn.Name.Name
+	}
+	return ""
+}
+
+// closureDoc returns the comment immediately preceding the innermost statement or
+// declaration of the given stack. The comment must be on the line right above the
+// statement and aligned with it, so trailing comments of the previous statement are not used.
+func closureDoc(fset *token.FileSet, stack []ast.Node, comments map[int][]*ast.CommentGroup) string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].(type) {
+		case ast.Stmt, ast.Decl:
+			pos := fset.Position(stack[i].Pos())
+			for _, cg := range comments[pos.Line-1] {
+				if fset.Position(cg.Pos()).Column == pos.Column {
+					return strings.TrimSpace(cg.Text())
+				}
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// parseFiles parses the given files in parallel and stores their documentation
+// in the cache. The files already in the cache are skipped. If files cannot be
+// parsed, the others are still stored and the error of the first one is returned.
+func (c *Cache) parseFiles(files []string) error {
+	todo := make([]string, 0, len(files))
+	for _, file := range files {
+		if _, ok := c.getFileDoc(file); !ok {
+			todo = append(todo, file)
+		}
+	}
+	if len(todo) == 0 {
+		return nil
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(todo) {
+		workers = len(todo)
+	}
+	errs := make([]error, len(todo))
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				doc, err := parseFileDoc(todo[i])
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				c.setFileDoc(todo[i], doc)
+			}
+		}()
+	}
+	for i := range todo {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return fmt.Errorf("openapi3: could not read the documentation of the handlers: %w", err)
+		}
+	}
+	return nil
+}
+
+// collectHandlerFiles returns the sorted list of the source files containing the
+// handlers of the routes of the given router and its subrouters. The excluded
+// routers and the handlers already documented in the cache are skipped.
+func (c *Cache) collectHandlerFiles(router *goyave.Router, excluded []*goyave.Router) []string {
+	files := make(map[string]struct{})
+	c.collectRouterFiles(router, excluded, files)
+	list := make([]string, 0, len(files))
+	for file := range files {
+		list = append(list, file)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func (c *Cache) collectRouterFiles(router *goyave.Router, excluded []*goyave.Router, files map[string]struct{}) {
+	for _, e := range excluded {
+		if e == router {
+			return
+		}
+	}
+
+	for _, route := range router.GetRoutes() {
+		pc := reflect.ValueOf(route.GetHandler()).Pointer()
+		if _, ok := c.getHandlerDoc(pc); ok || isStaticRoute(route) {
+			continue
+		}
+		file, _ := runtime.FuncForPC(pc).FileLine(pc)
+		if file != "<autogenerated>" {
+			files[file] = struct{}{}
+		}
+	}
+
+	for _, subrouter := range router.GetSubrouters() {
+		c.collectRouterFiles(subrouter, excluded, files)
+	}
+}
@@ -0,0 +1,184 @@
+package openapi3
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+	"goyave.dev/goyave/v4/validation"
+	"goyave.dev/openapi3/testdata/benchmark"
+	"goyave.dev/openapi3/testdata/controller/user"
+)
+
+type SourceTestSuite struct {
+	suite.Suite
+}
+
+func (suite *SourceTestSuite) TestParseFileDoc() {
+	doc, err := parseFileDoc("route_test.go")
+	suite.Require().NoError(err)
+
+	suite.Equal("HandlerTest a test handler for AST reading", doc.Funcs["HandlerTest"])
+	suite.NotContains(doc.Funcs, "(*testController).handlerStar")
+	suite.Equal("documentedClosure a closure handler declared at package level", doc.Closures[suite.closureLine(documentedClosure)])
+
+	_, err = parseFileDoc("notafile")
+	suite.Error(err)
+	_, err = parseFileDoc("go.mod")
+	suite.Error(err)
+}
+
+func (suite *SourceTestSuite) closureLine(handler goyave.Handler) int {
+	pc := reflect.ValueOf(handler).Pointer()
+	_, line := runtime.FuncForPC(pc).FileLine(pc)
+	return line
+}
+
+func (suite *SourceTestSuite) TestFuncDoc() {
+	doc := &FileDoc{
+		Funcs: map[string]string{
+			"Index":                "Index doc",
+			"(*Controller).Store":  "Store doc",
+			"Controller.Update":    "Update doc",
+			"(*Controller).Delete": "",
+		},
+	}
+	suite.Equal("Index doc", doc.FuncDoc("goyave.dev/app/http/controller/user.Index"))
+	suite.Equal("Store doc", doc.FuncDoc("goyave.dev/app/http/controller/user.(*Controller).Store-fm"))
+	suite.Equal("Update doc", doc.FuncDoc("goyave.dev/app/http/controller/user.Controller.Update-fm"))
+	suite.Equal("Index doc", doc.FuncDoc("example.com.Index"))
+	suite.Empty(doc.FuncDoc("goyave.dev/app/http/controller/user.(*Controller).Delete-fm"))
+	suite.Empty(doc.FuncDoc("goyave.dev/app/http/controller/user.Show"))
+}
+
+func (suite *SourceTestSuite) TestParseFiles() {
+	cache := NewCache()
+	existing := &FileDoc{}
+	cache.Files["route.go"] = existing
+
+	err := cache.parseFiles([]string{"route.go", "route_test.go", "source_test.go", "notafile", "go.mod"})
+	suite.Require().Error(err)
+	suite.ErrorIs(err, os.ErrNotExist) // Error of the first file that cannot be parsed
+	suite.Same(existing, cache.Files["route.go"])
+	suite.Contains(cache.Files, "route_test.go")
+	suite.Contains(cache.Files, "source_test.go")
+	suite.NotContains(cache.Files, "notafile")
+	suite.NotContains(cache.Files, "go.mod")
+
+	suite.Require().NoError(cache.parseFiles(nil))
+	suite.Len(cache.Files, 3)
+	suite.Require().NoError(cache.parseFiles([]string{"route.go", "route_test.go"}))
+	suite.Len(cache.Files, 3)
+}
+
+func (suite *SourceTestSuite) TestCollectHandlerFiles() {
+	router := goyave.NewRouter()
+	router.Get("/test", HandlerTest)
+	subrouter := router.Subrouter("/users")
+	subrouter.Get("/", user.Index)
+
+	cache := NewCache()
+	files := cache.collectHandlerFiles(router, nil)
+	suite.Len(files, 2)
+	suite.Equal("route_test.go", filepath.Base(files[0]))
+	suite.Equal("user.go", filepath.Base(files[1]))
+
+	files = cache.collectHandlerFiles(router, []*goyave.Router{subrouter})
+	suite.Len(files, 1)
+	suite.Equal("route_test.go", filepath.Base(files[0]))
+
+	// Handlers already documented are skipped
+	cache.HandlerDocs[reflect.ValueOf(HandlerTest).Pointer()] = &HandlerDoc{}
+	files = cache.collectHandlerFiles(router, nil)
+	suite.Len(files, 1)
+	suite.Equal("user.go", filepath.Base(files[0]))
+}
+
+func TestSourceSuite(t *testing.T) {
+	suite.Run(t, new(SourceTestSuite))
+}
+
+func makeBenchmarkRouter(routes int) *goyave.Router {
+	rules := &validation.Rules{
+		Fields: validation.FieldMap{
+			"name": &validation.Field{Rules: []*validation.Rule{{Name: "required"}, {Name: "string"}}},
+		},
+	}
+	// The routes reuse a handful of handlers spread across several files, so the
+	// source files are still parsed in parallel
+	router := goyave.NewRouter()
+	for i := 0; i < routes; i++ {
+		subrouter := router.Subrouter(fmt.Sprintf("/resource%d", i/10))
+		subrouter.Post(fmt.Sprintf("/action%d/{id:[0-9]+}", i), benchmark.Handlers[i%len(benchmark.Handlers)]).Validate(rules)
+	}
+	return router
+}
+
+func loadBenchmarkConfig(b *testing.B) {
+	if err := config.LoadJSON(`{
+			"app": {
+				"name": "Benchmark"
+			},
+			"server": {
+				"protocol": "http",
+				"domain": "goyave.dev",
+				"port": 80
+			}
+		}`); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	loadBenchmarkConfig(b)
+	for _, routes := range []int{1000, 5000} {
+		router := makeBenchmarkRouter(routes)
+		b.Run(fmt.Sprintf("%d routes", routes), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				NewGenerator().Generate(router)
+			}
+		})
+		b.Run(fmt.Sprintf("%d routes shared cache", routes), func(b *testing.B) {
+			generator := NewGenerator()
+			generator.Generate(router)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				generator.Generate(router)
+			}
+		})
+	}
+}
+
+func BenchmarkParseFiles(b *testing.B) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("serial", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, file := range files {
+				if _, err := parseFileDoc(file); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := NewCache().parseFiles(files); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
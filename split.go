@@ -0,0 +1,307 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"goyave.dev/goyave/v4"
+)
+
+var refFormat = regexp.MustCompile(`"\$ref":"#/components/(\w+)/([^"]+)"`)
+
+// SplitFunc returns the prefix identifying the spec the given route belongs to when
+// using Generator.GenerateSplit. The given uri is the route's full URI, without the parameters
+// patterns. If an empty string is returned, the route is not included in any spec.
+//
+// The returned prefix must be a prefix of the given uri.
+type SplitFunc func(route *goyave.Route, uri string) string
+
+// GenerateSplit generate an OpenAPI 3 specification for each prefix returned by the given
+// SplitFunc, based on the given Router.
+//
+// In each spec, the prefix is removed from the paths and appended to the servers URL.
+// All specs share the same component names and definitions, but only contain the components
+// and tags they reference, including in the "x-tagGroups" extension. Security schemes are
+// kept in all specs. The specs don't share any value: modifying one of them doesn't affect
+// the others.
+//
+// The returned map is indexed by prefix.
+//
+// If an error occurs, it is printed and nil is returned. Use GenerateSplitE to handle
+// the error.
+func (g *Generator) GenerateSplit(router *goyave.Router, split SplitFunc) map[string]*openapi3.T {
+	specs, err := g.GenerateSplitE(router, split)
+	if err != nil {
+		fmt.Println(err)
+		return nil
+	}
+	return specs
+}
+
+// GenerateSplitE is the same as GenerateSplit but returns the error instead of printing it.
+// See GenerateE.
+func (g *Generator) GenerateSplitE(router *goyave.Router, split SplitFunc) (map[string]*openapi3.T, error) {
+	spec, err := g.GenerateE(router)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make(map[string]*openapi3.T)
+	if err := splitRouter(router, split, spec, specs); err != nil {
+		return nil, err
+	}
+
+	components, err := marshalComponents(spec.Components)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range specs {
+		if s.Components, err = pruneComponents(s.Paths, components); err != nil {
+			return nil, err
+		}
+		used := usedTags(s.Paths)
+		s.Tags = pruneTags(used, s.Tags)
+		s.Extensions = pruneTagGroups(used, s.Extensions)
+	}
+	return specs, nil
+}
+
+func splitRouter(router *goyave.Router, split SplitFunc, spec *openapi3.T, specs map[string]*openapi3.T) error {
+	for _, route := range router.GetRoutes() {
+		uri := cleanPath(route)
+		pathItem := spec.Paths[uri]
+		if pathItem == nil {
+			continue
+		}
+		prefix := split(route, uri)
+		if prefix == "" {
+			continue
+		}
+		s, ok := specs[prefix]
+		if !ok {
+			var err error
+			if s, err = newSplitSpec(spec, prefix); err != nil {
+				return err
+			}
+			specs[prefix] = s
+		}
+		path := strings.TrimPrefix(uri, prefix)
+		if path == "" {
+			path = "/"
+		}
+		if _, exists := s.Paths[path]; !exists {
+			copied := &openapi3.PathItem{}
+			if err := deepCopy(pathItem, copied); err != nil {
+				return err
+			}
+			s.Paths[path] = copied
+		}
+	}
+
+	for _, subrouter := range router.GetSubrouters() {
+		if err := splitRouter(subrouter, split, spec, specs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newSplitSpec returns a deep copy of the top-level fields of the given spec, with
+// the given prefix appended to the servers URL. The paths are empty and the
+// components are not copied.
+func newSplitSpec(spec *openapi3.T, prefix string) (*openapi3.T, error) {
+	top := &openapi3.T{
+		Extensions:   spec.Extensions,
+		OpenAPI:      spec.OpenAPI,
+		Info:         spec.Info,
+		Security:     spec.Security,
+		Servers:      spec.Servers,
+		Tags:         spec.Tags,
+		ExternalDocs: spec.ExternalDocs,
+	}
+	s := &openapi3.T{}
+	if err := deepCopy(top, s); err != nil {
+		return nil, err
+	}
+	s.Paths = make(openapi3.Paths)
+	for _, server := range s.Servers {
+		server.URL = strings.TrimSuffix(server.URL, "/") + prefix
+	}
+	return s, nil
+}
+
+// deepCopy copies src into dst using their JSON representation.
+func deepCopy(src, dst interface{}) error {
+	b, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+func marshalComponents(components *openapi3.Components) (map[string]json.RawMessage, error) {
+	m := make(map[string]json.RawMessage)
+	if err := deepCopy(components, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func pruneComponents(paths openapi3.Paths, components map[string]json.RawMessage) (*openapi3.Components, error) {
+	all := make(map[string]map[string]json.RawMessage, len(components))
+	pruned := make(map[string]interface{}, len(components))
+	for kind, c := range components {
+		if strings.HasPrefix(kind, "x-") || kind == "securitySchemes" {
+			pruned[kind] = c
+			continue
+		}
+		m := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(c, &m); err != nil {
+			return nil, err
+		}
+		all[kind] = m
+	}
+
+	b, err := json.Marshal(paths)
+	if err != nil {
+		return nil, err
+	}
+	prunedKinds := make(map[string]map[string]json.RawMessage, len(all))
+	queue := refFormat.FindAllSubmatch(b, -1)
+	for len(queue) > 0 {
+		kind, name := string(queue[0][1]), string(queue[0][2])
+		queue = queue[1:]
+		if _, ok := prunedKinds[kind][name]; ok {
+			continue
+		}
+		c, ok := all[kind][name]
+		if !ok {
+			continue
+		}
+		if prunedKinds[kind] == nil {
+			prunedKinds[kind] = make(map[string]json.RawMessage)
+			pruned[kind] = prunedKinds[kind]
+		}
+		prunedKinds[kind][name] = c
+		queue = append(queue, refFormat.FindAllSubmatch(c, -1)...)
+	}
+
+	result := &openapi3.Components{}
+	if err := deepCopy(pruned, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// usedTags returns the set of tags used by the operations of the given paths.
+func usedTags(paths openapi3.Paths) map[string]struct{} {
+	used := make(map[string]struct{})
+	for _, pathItem := range paths {
+		for _, op := range pathItem.Operations() {
+			for _, t := range op.Tags {
+				used[t] = struct{}{}
+			}
+		}
+	}
+	return used
+}
+
+func pruneTags(used map[string]struct{}, tags openapi3.Tags) openapi3.Tags {
+	pruned := make(openapi3.Tags, 0, len(used))
+	for _, t := range tags {
+		if _, ok := used[t.Name]; ok {
+			pruned = append(pruned, t)
+		}
+	}
+	return pruned
+}
+
+// pruneTagGroups returns a copy of the given extensions whose "x-tagGroups" only contain
+// the used tags. Empty groups are removed. The extension can be a []*TagGroup or its JSON
+// representation (if the spec has been merged or patched). It is kept as is if it cannot
+// be read.
+func pruneTagGroups(used map[string]struct{}, extensions map[string]interface{}) map[string]interface{} {
+	value, ok := extensions["x-tagGroups"]
+	if !ok {
+		return extensions
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return extensions
+	}
+	groups := []*TagGroup{}
+	if err := json.Unmarshal(b, &groups); err != nil {
+		return extensions
+	}
+
+	pruned := make([]*TagGroup, 0, len(groups))
+	for _, group := range groups {
+		tags := make([]string, 0, len(group.Tags))
+		for _, t := range group.Tags {
+			if _, ok := used[t]; ok {
+				tags = append(tags, t)
+			}
+		}
+		if len(tags) != 0 {
+			pruned = append(pruned, &TagGroup{Name: group.Name, Tags: tags})
+		}
+	}
+
+	result := make(map[string]interface{}, len(extensions))
+	for k, v := range extensions {
+		result[k] = v
+	}
+	if len(pruned) == 0 {
+		delete(result, "x-tagGroups")
+	} else {
+		result["x-tagGroups"] = pruned
+	}
+	return result
+}
+
+// SplitByFirstSegment SplitFunc using the first segment of the route's URI as prefix.
+// For example, "/v1/users" is put in the "/v1" spec. Routes with a parameter as first segment
+// are not included in any spec.
+func SplitByFirstSegment(_ *goyave.Route, uri string) string {
+	segment := firstSegment(uri)
+	if segment == "" || isParameter(segment) {
+		return ""
+	}
+	return "/" + segment
+}
+
+// SplitByPrefix returns a SplitFunc putting the routes in the spec of the longest given
+// prefix they start with. Routes matching none of the given prefixes are not included
+// in any spec.
+func SplitByPrefix(prefixes ...string) SplitFunc {
+	return func(_ *goyave.Route, uri string) string {
+		match := ""
+		for _, p := range prefixes {
+			p = strings.TrimSuffix(p, "/")
+			if (uri == p || strings.HasPrefix(uri, p+"/")) && len(p) > len(match) {
+				match = p
+			}
+		}
+		return match
+	}
+}
+
+// SplitBySubrouter returns a SplitFunc putting the routes belonging to one of the given
+// subrouters (or their subrouters) in the spec identified by the associated prefix.
+// The prefix must be the full prefix of the subrouter, for example "/v1".
+// Routes belonging to none of the given subrouters are not included in any spec.
+func SplitBySubrouter(subrouters map[string]*goyave.Router) SplitFunc {
+	return func(route *goyave.Route, _ string) string {
+		for router := route.GetParent(); router != nil; router = router.GetParent() {
+			for prefix, subrouter := range subrouters {
+				if subrouter == router {
+					return prefix
+				}
+			}
+		}
+		return ""
+	}
+}
@@ -0,0 +1,183 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+)
+
+type SplitTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *SplitTestSuite) TestGenerateSplit() {
+	router := goyave.NewRouter()
+	router.Get("/{param}", HandlerTest)
+	v1 := router.Subrouter("/v1")
+	v1.Get("/", HandlerTest)
+	v1.Get("/users", HandlerTest)
+	v1.Get("/users/{id:[0-9]+}", HandlerTest)
+	v2 := router.Subrouter("/v2")
+	v2.Get("/users/{name}", HandlerTest)
+
+	specs := NewGenerator().GenerateSplit(router, SplitByFirstSegment)
+	suite.Len(specs, 2)
+	suite.Contains(specs, "/v1")
+	suite.Contains(specs, "/v2")
+
+	v1Spec := specs["/v1"]
+	suite.Len(v1Spec.Paths, 3)
+	suite.Contains(v1Spec.Paths, "/")
+	suite.Contains(v1Spec.Paths, "/users")
+	suite.Contains(v1Spec.Paths, "/users/{id}")
+	suite.Equal("http://goyave.dev/v1", v1Spec.Servers[0].URL)
+	suite.Equal("Generator", v1Spec.Info.Title)
+	suite.Contains(v1Spec.Components.Parameters, "id")
+	suite.NotContains(v1Spec.Components.Parameters, "name")
+	suite.NotContains(v1Spec.Components.Parameters, "param")
+	suite.Contains(v1Spec.Components.Schemas, "paramInteger")
+	suite.NotContains(v1Spec.Components.Schemas, "paramString")
+
+	v2Spec := specs["/v2"]
+	suite.Len(v2Spec.Paths, 1)
+	suite.Contains(v2Spec.Paths, "/users/{name}")
+	suite.Equal("http://goyave.dev/v2", v2Spec.Servers[0].URL)
+	suite.Contains(v2Spec.Components.Parameters, "name")
+	suite.NotContains(v2Spec.Components.Parameters, "id")
+	suite.Contains(v2Spec.Components.Schemas, "paramString")
+	suite.NotContains(v2Spec.Components.Schemas, "paramInteger")
+
+	suite.Len(v1Spec.Tags, 1)
+	suite.NotNil(v1Spec.Tags.Get("v1"))
+	suite.Len(v2Spec.Tags, 1)
+	suite.NotNil(v2Spec.Tags.Get("v2"))
+}
+
+func (suite *SplitTestSuite) TestGenerateSplitTagGroups() {
+	router := goyave.NewRouter()
+	router.Subrouter("/v1").Get("/users", HandlerTest).Name("users.index")
+	router.Subrouter("/v2").Get("/products", HandlerTest).Name("products.index")
+
+	generator := NewGenerator()
+	generator.TagFunc = TagRouteName(".")
+	generator.TagGroups = []*TagGroup{
+		{Name: "Accounts", Tags: []string{"users"}},
+		{Name: "Shop", Tags: []string{"products", "orders"}},
+	}
+	specs := generator.GenerateSplit(router, SplitByFirstSegment)
+
+	suite.Equal([]*TagGroup{{Name: "Accounts", Tags: []string{"users"}}}, specs["/v1"].Extensions["x-tagGroups"])
+	suite.Equal([]*TagGroup{{Name: "Shop", Tags: []string{"products"}}}, specs["/v2"].Extensions["x-tagGroups"])
+	suite.Len(generator.TagGroups[1].Tags, 2) // Not modified
+}
+
+func (suite *SplitTestSuite) TestGenerateSplitIndependent() {
+	router := goyave.NewRouter()
+	router.Subrouter("/v1").Get("/users", HandlerTest)
+	router.Subrouter("/v2").Get("/users", HandlerTest)
+
+	generator := NewGenerator()
+	generator.Base = &openapi3.T{
+		Info: &openapi3.Info{Title: "Base", Version: "0"},
+		Extensions: map[string]interface{}{
+			"x-logo": map[string]interface{}{"url": "logo.png"},
+		},
+	}
+	specs, err := generator.GenerateSplitE(router, SplitByFirstSegment)
+	suite.Require().NoError(err)
+
+	specs["/v1"].Info.Version = "1"
+	specs["/v1"].Extensions["x-logo"].(map[string]interface{})["url"] = "v1.png"
+	specs["/v1"].Paths["/users"].Get.Summary = "v1"
+
+	suite.Equal("0", specs["/v2"].Info.Version)
+	suite.Equal("0", generator.Base.Info.Version)
+	suite.Equal("logo.png", specs["/v2"].Extensions["x-logo"].(map[string]interface{})["url"])
+	suite.Equal("logo.png", generator.Base.Extensions["x-logo"].(map[string]interface{})["url"])
+	suite.NotEqual("v1", specs["/v2"].Paths["/users"].Get.Summary)
+}
+
+func (suite *SplitTestSuite) TestPruneTagGroups() {
+	used := map[string]struct{}{"users": {}}
+	extensions := map[string]interface{}{
+		"x-logo": "logo.png",
+		// Generic JSON representation, after a merge or a patch
+		"x-tagGroups": []interface{}{
+			map[string]interface{}{"name": "Accounts", "tags": []interface{}{"users", "roles"}},
+			map[string]interface{}{"name": "Shop", "tags": []interface{}{"products"}},
+		},
+	}
+	pruned := pruneTagGroups(used, extensions)
+	suite.Equal("logo.png", pruned["x-logo"])
+	suite.Equal([]*TagGroup{{Name: "Accounts", Tags: []string{"users"}}}, pruned["x-tagGroups"])
+	suite.Len(extensions["x-tagGroups"], 2) // Not modified
+
+	pruned = pruneTagGroups(map[string]struct{}{}, extensions)
+	suite.NotContains(pruned, "x-tagGroups")
+
+	suite.Nil(pruneTagGroups(used, nil))
+}
+
+func (suite *SplitTestSuite) TestGenerateSplitSecuritySchemes() {
+	router := goyave.NewRouter()
+	router.Subrouter("/v1").Get("/users", HandlerTest)
+
+	generator := NewGenerator()
+	generator.AddOpenIDConnect("oidc", "https://example.org")
+	specs := generator.GenerateSplit(router, SplitByFirstSegment)
+	suite.Contains(specs["/v1"].Components.SecuritySchemes, "oidc")
+	suite.Len(specs["/v1"].Security, 1)
+}
+
+func (suite *SplitTestSuite) TestSplitByFirstSegment() {
+	suite.Equal("/v1", SplitByFirstSegment(nil, "/v1/users"))
+	suite.Equal("/v1", SplitByFirstSegment(nil, "/v1"))
+	suite.Empty(SplitByFirstSegment(nil, "/{version}/users"))
+	suite.Empty(SplitByFirstSegment(nil, "/"))
+}
+
+func (suite *SplitTestSuite) TestSplitByPrefix() {
+	split := SplitByPrefix("/v1", "/v1/admin/", "/v2")
+	suite.Equal("/v1", split(nil, "/v1"))
+	suite.Equal("/v1", split(nil, "/v1/users"))
+	suite.Equal("/v1/admin", split(nil, "/v1/admin/users"))
+	suite.Equal("/v2", split(nil, "/v2/users"))
+	suite.Empty(split(nil, "/v10/users"))
+	suite.Empty(split(nil, "/users"))
+}
+
+func (suite *SplitTestSuite) TestSplitBySubrouter() {
+	router := goyave.NewRouter()
+	public := router.Subrouter("/public")
+	admin := router.Subrouter("/admin")
+	publicRoute := public.Subrouter("/users").Get("/", HandlerTest)
+	adminRoute := admin.Get("/users", HandlerTest)
+	otherRoute := router.Get("/other", HandlerTest)
+
+	split := SplitBySubrouter(map[string]*goyave.Router{
+		"/public": public,
+		"/admin":  admin,
+	})
+	suite.Equal("/public", split(publicRoute, "/public/users"))
+	suite.Equal("/admin", split(adminRoute, "/admin/users"))
+	suite.Empty(split(otherRoute, "/other"))
+}
+
+func TestSplitSuite(t *testing.T) {
+	if err := config.LoadJSON(`{
+			"app": {
+				"name": "Generator"
+			},
+			"server": {
+				"protocol": "http",
+				"domain": "goyave.dev",
+				"port": 80
+			}
+		}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(SplitTestSuite))
+}
@@ -0,0 +1,61 @@
+package openapi3
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"goyave.dev/goyave/v4"
+)
+
+// staticHandlerPrefix the name prefix of the handlers created by goyave.Router.Static.
+const staticHandlerPrefix = "goyave.dev/goyave/v4.staticHandler."
+
+const staticDescription = "Serve a static file."
+
+// isStaticRoute returns true if the given route was registered using goyave.Router.Static.
+func isStaticRoute(route *goyave.Route) bool {
+	pc := reflect.ValueOf(route.GetHandler()).Pointer()
+	fn := runtime.FuncForPC(pc)
+	return fn != nil && strings.HasPrefix(fn.Name(), staticHandlerPrefix)
+}
+
+// staticOperationID returns the operationId of the given static route, generated
+// from the route's URI. For example, "/public/{resource}" gives "static.public".
+func staticOperationID(route *goyave.Route) string {
+	uri := cleanPath(route)
+	if i := strings.LastIndex(uri, "{"); i != -1 {
+		uri = uri[:i]
+	}
+	uri = strings.Trim(uri, "/")
+	if uri == "" {
+		return "static"
+	}
+	return "static." + strings.ReplaceAll(uri, "/", ".")
+}
+
+// convertStaticResponses documents the responses of an operation generated from a static route.
+func convertStaticResponses(op *openapi3.Operation) {
+	file := openapi3.NewResponse().
+		WithDescription("The requested file. Its content type is guessed from the file's extension and content, and defaults to `application/octet-stream`.").
+		WithContent(openapi3.NewContentWithSchema(openapi3.NewStringSchema().WithFormat("binary"), []string{"application/octet-stream"}))
+	file.Headers = openapi3.Headers{
+		"Content-Disposition": newHeader("`inline`, or `attachment` if the directory is served for download.", openapi3.NewStringSchema()),
+	}
+
+	op.Responses = openapi3.Responses{
+		"200": &openapi3.ResponseRef{Value: file},
+		"404": &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("File not found.")},
+	}
+}
+
+// describeStaticParameter documents the "resource" path parameter of a static route.
+func describeStaticParameter(path *openapi3.PathItem, spec *openapi3.T) {
+	for _, ref := range path.Parameters {
+		param := spec.Components.Parameters[strings.TrimPrefix(ref.Ref, "#/components/parameters/")]
+		if param != nil && param.Value.Name == "resource" && param.Value.Description == "" {
+			param.Value.Description = "Path of the file, relative to the served directory."
+		}
+	}
+}
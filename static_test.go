@@ -0,0 +1,87 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+)
+
+type StaticTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *StaticTestSuite) TestIsStaticRoute() {
+	router := goyave.NewRouter()
+	suite.True(isStaticRoute(router.Static("/public", "testdata", false)))
+	suite.False(isStaticRoute(router.Get("/test", HandlerTest)))
+}
+
+func (suite *StaticTestSuite) TestStaticOperationID() {
+	router := goyave.NewRouter()
+	suite.Equal("static.public", staticOperationID(router.Static("/public", "testdata", false)))
+	suite.Equal("static.assets.images", staticOperationID(router.Subrouter("/assets").Static("/images/", "testdata", false)))
+	suite.Equal("static", staticOperationID(router.Static("/", "testdata", false)))
+
+	route := router.Static("/named", "testdata", false).Name("files")
+	suite.Equal("files", DefaultOperationID(route, "GET", ""))
+	route = router.Static("/docs", "testdata", false)
+	suite.Equal("static.docs", DefaultOperationID(route, "GET", ""))
+}
+
+func (suite *StaticTestSuite) TestGenerateStatic() {
+	router := goyave.NewRouter()
+	router.Static("/public", "testdata", true)
+	router.Get("/test", HandlerTest)
+
+	spec := NewGenerator().Generate(router)
+	suite.Contains(spec.Paths, "/public{resource}")
+	pathItem := spec.Paths["/public{resource}"]
+	suite.Nil(pathItem.Head)
+
+	op := pathItem.Get
+	suite.Equal("static.public", op.OperationID)
+	suite.Equal("Serve a static file.", op.Summary)
+	suite.Len(op.Responses, 2)
+	suite.Contains(op.Responses, "404")
+	ok := op.Responses.Get(200)
+	suite.Require().NotNil(ok)
+	suite.Contains(ok.Value.Content, "application/octet-stream")
+	suite.Equal("binary", ok.Value.Content["application/octet-stream"].Schema.Value.Format)
+	suite.Contains(ok.Value.Headers, "Content-Disposition")
+
+	suite.Require().Len(pathItem.Parameters, 1)
+	suite.Equal("#/components/parameters/resource", pathItem.Parameters[0].Ref)
+	suite.NotEmpty(spec.Components.Parameters["resource"].Value.Description)
+
+	suite.Contains(spec.Paths["/test"].Get.Responses, "default")
+}
+
+func (suite *StaticTestSuite) TestExcludeStatic() {
+	router := goyave.NewRouter()
+	router.Static("/public", "testdata", false)
+	router.Get("/test", HandlerTest)
+
+	generator := NewGenerator()
+	generator.ExcludeStatic = true
+	spec := generator.Generate(router)
+	suite.NotContains(spec.Paths, "/public{resource}")
+	suite.Contains(spec.Paths, "/test")
+}
+
+func TestStaticSuite(t *testing.T) {
+	if err := config.LoadJSON(`{
+			"app": {
+				"name": "Generator"
+			},
+			"server": {
+				"protocol": "http",
+				"domain": "goyave.dev",
+				"port": 80
+			}
+		}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(StaticTestSuite))
+}
@@ -0,0 +1,29 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE-GO file.
+
+package openapi3
+
+import "unicode"
+
+// firstSentence returns the first sentence in s.
+// The sentence ends after the first period followed by space and
+// not preceded by exactly one uppercase letter.
+//
+// Copied from go/doc (src/go/doc/synopsis.go), the logic used for package synopsis.
+func firstSentence(s string) string {
+	var ppp, pp, p rune
+	for i, q := range s {
+		if q == '\n' || q == '\r' || q == '\t' {
+			q = ' '
+		}
+		if q == ' ' && p == '.' && (!unicode.IsUpper(pp) || unicode.IsUpper(ppp)) {
+			return s[:i]
+		}
+		if p == '。' || p == '．' {
+			return s[:i]
+		}
+		ppp, pp, p = pp, p, q
+	}
+	return s
+}
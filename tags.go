@@ -0,0 +1,208 @@
+package openapi3
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"goyave.dev/goyave/v4"
+)
+
+// TagFunc returns the tag of the operations generated from the given route.
+// The given uri is the route's full URI, without the parameters patterns.
+// If an empty string is returned, the operations are not tagged.
+type TagFunc func(route *goyave.Route, uri string) string
+
+// TagGroup a group of tags, generated in the "x-tagGroups" extension of the spec.
+type TagGroup struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// TagFirstSegment TagFunc using the first segment of the route's URI. If the first
+// segment is a parameter, the route is not tagged.
+func TagFirstSegment(_ *goyave.Route, uri string) string {
+	tag := firstSegment(uri)
+	if isParameter(tag) {
+		return ""
+	}
+	return tag
+}
+
+// TagFirstSegmentAfter returns a TagFunc using the first segment of the route's URI
+// that is not a parameter, after the given prefix. For example, with the "/v1" prefix,
+// "/v1/{tenant}/users" is tagged "users".
+func TagFirstSegmentAfter(prefix string) TagFunc {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return func(_ *goyave.Route, uri string) string {
+		if uri == prefix || strings.HasPrefix(uri, prefix+"/") {
+			uri = uri[len(prefix):]
+		}
+		for _, segment := range strings.Split(uri, "/") {
+			if segment != "" && !isParameter(segment) {
+				return segment
+			}
+		}
+		return ""
+	}
+}
+
+// TagSubrouter TagFunc using the last segment of the route's parent router prefix
+// that is not a parameter. For example, a route registered in the subrouter "/users/{id}"
+// is tagged "users".
+func TagSubrouter(route *goyave.Route, _ string) string {
+	prefix := strings.TrimSuffix(route.GetFullURI(), route.GetURI())
+	segments := strings.Split(prefix, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] != "" && !isParameter(segments[i]) {
+			return segments[i]
+		}
+	}
+	return ""
+}
+
+// TagControllerPackage TagFunc using the name of the package of the route's handler.
+// For example, a route using the handler "myapp/http/controller/user.(*Controller).Update"
+// is tagged "user".
+func TagControllerPackage(route *goyave.Route, _ string) string {
+	handler := runtime.FuncForPC(reflect.ValueOf(route.GetHandler()).Pointer())
+	if handler == nil {
+		return ""
+	}
+	return packageName(handler.Name())
+}
+
+// TagRouteName returns a TagFunc using the part of the route's name before the first
+// occurrence of the given separator. For example, with the "." separator, the route named
+// "user.update" is tagged "user". Routes without a name are not tagged. If the separator
+// is empty, the whole name is used.
+func TagRouteName(separator string) TagFunc {
+	return func(route *goyave.Route, _ string) string {
+		name := route.GetName()
+		if separator == "" {
+			return name
+		}
+		if i := strings.Index(name, separator); i != -1 {
+			return name[:i]
+		}
+		return name
+	}
+}
+
+func packageName(funcName string) string {
+	name := funcName[strings.LastIndex(funcName, "/")+1:]
+	if i := strings.Index(name, "."); i != -1 {
+		return name[:i]
+	}
+	return name
+}
+
+func (c *RouteConverter) convertTag() string {
+	if c.generator.TagFunc != nil {
+		return c.generator.TagFunc(c.route, c.uri)
+	}
+	return c.uriToTag(c.uri)
+}
+
+func (c *RouteConverter) addTag(spec *openapi3.T) {
+	tag := spec.Tags.Get(c.tag)
+	if tag == nil {
+		tag = &openapi3.Tag{Name: c.tag}
+		spec.Tags = append(spec.Tags, tag)
+	}
+	if tag.Description == "" && c.tag == packageName(c.funcName) {
+		// The operations are grouped by controller package, document the tag
+		// using the package documentation.
+		tag.Description = goDocToMarkdown(c.readPackageDoc())
+	}
+}
+
+func (c *RouteConverter) readPackageDoc() string {
+	pc := reflect.ValueOf(c.route.GetHandler()).Pointer()
+	file, _ := runtime.FuncForPC(pc).FileLine(pc)
+	if file == "<autogenerated>" {
+		// Fix for Go 1.18 change
+		// https://github.com/golang/go/issues/51774
+		return ""
+	}
+	return c.refs.packageDoc(file)
+}
+
+// packageDoc returns the documentation of the package the given source file belongs to.
+// The results are cached by directory. If the package cannot be parsed, its documentation
+// is considered empty so the generation doesn't fail because of an optional description.
+func (c *Cache) packageDoc(file string) string {
+	dir := filepath.Dir(file)
+	if cached, ok := c.getPackageDoc(dir); ok {
+		return cached
+	}
+
+	fset := token.NewFileSet()
+	// ParseDir returns the packages parsed before an error: use them anyway.
+	pkgs, _ := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.PackageClauseOnly|parser.ParseComments)
+
+	docs := ""
+	if pkg := findFilePackage(pkgs, file); pkg != nil {
+		files := make([]*ast.File, 0, len(pkg.Files))
+		for _, f := range pkg.Files {
+			files = append(files, f)
+		}
+		// Like godoc, the package comments of all the files are used
+		if p, err := doc.NewFromFiles(fset, files, dir); err == nil {
+			docs = strings.TrimSpace(p.Doc)
+		}
+	}
+
+	c.setPackageDoc(dir, docs)
+	return docs
+}
+
+// findFilePackage returns the package containing the given file. The packages are
+// indexed by their declared name, which can be different from the last segment of
+// their import path (directory name, major version suffix), so they are matched
+// by file. If the file is not found, the only package of the directory is returned.
+func findFilePackage(pkgs map[string]*ast.Package, file string) *ast.Package {
+	for _, pkg := range pkgs {
+		if _, ok := pkg.Files[file]; ok {
+			return pkg
+		}
+	}
+	if len(pkgs) == 1 {
+		for _, pkg := range pkgs {
+			return pkg
+		}
+	}
+	return nil
+}
+
+func (g *generation) convertTags() {
+	for name, description := range g.TagDescriptions {
+		tag := g.spec.Tags.Get(name)
+		if tag == nil {
+			tag = &openapi3.Tag{Name: name}
+			g.spec.Tags = append(g.spec.Tags, tag)
+		}
+		tag.Description = description
+	}
+
+	sort.Slice(g.spec.Tags, func(i, j int) bool {
+		return g.spec.Tags[i].Name < g.spec.Tags[j].Name
+	})
+
+	if len(g.TagGroups) != 0 {
+		if g.spec.Extensions == nil {
+			g.spec.Extensions = make(map[string]interface{}, 1)
+		}
+		g.spec.Extensions["x-tagGroups"] = g.TagGroups
+	}
+}
@@ -0,0 +1,180 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+	product "goyave.dev/openapi3/testdata/controller/products"
+	"goyave.dev/openapi3/testdata/controller/user"
+)
+
+type TagsTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *TagsTestSuite) TestTagFirstSegment() {
+	suite.Equal("products", TagFirstSegment(nil, "/products/{id}"))
+	suite.Equal("products", TagFirstSegment(nil, "products/{id}"))
+	suite.Empty(TagFirstSegment(nil, "/{id}"))
+	suite.Empty(TagFirstSegment(nil, "/"))
+}
+
+func (suite *TagsTestSuite) TestTagFirstSegmentAfter() {
+	tagFunc := TagFirstSegmentAfter("/v1/")
+	suite.Equal("users", tagFunc(nil, "/v1/users/{id}"))
+	suite.Equal("users", tagFunc(nil, "/v1/{tenant}/users"))
+	suite.Equal("v10", tagFunc(nil, "/v10/users"))
+	suite.Empty(tagFunc(nil, "/v1"))
+	suite.Empty(tagFunc(nil, "/v1/{id}"))
+
+	tagFunc = TagFirstSegmentAfter("")
+	suite.Equal("users", tagFunc(nil, "/{tenant}/users"))
+}
+
+func (suite *TagsTestSuite) TestTagSubrouter() {
+	router := goyave.NewRouter()
+	route := router.Subrouter("/v1").Subrouter("/users/{id:[0-9]+}").Get("/profile", HandlerTest)
+	suite.Equal("users", TagSubrouter(route, "/v1/users/{id}/profile"))
+
+	route = router.Get("/profile", HandlerTest)
+	suite.Empty(TagSubrouter(route, "/profile"))
+}
+
+func (suite *TagsTestSuite) TestTagControllerPackage() {
+	router := goyave.NewRouter()
+	route := router.Get("/test", HandlerTest)
+	suite.Equal("openapi3", TagControllerPackage(route, "/test"))
+
+	ctrl := &testController{}
+	route = router.Get("/test", ctrl.handlerStar)
+	suite.Equal("openapi3", TagControllerPackage(route, "/test"))
+}
+
+func (suite *TagsTestSuite) TestTagRouteName() {
+	router := goyave.NewRouter()
+	tagFunc := TagRouteName(".")
+	suite.Equal("user", tagFunc(router.Get("/users", HandlerTest).Name("user.index"), "/users"))
+	suite.Equal("user", tagFunc(router.Get("/user", HandlerTest).Name("user"), "/user"))
+	suite.Empty(tagFunc(router.Get("/unnamed", HandlerTest), "/unnamed"))
+
+	tagFunc = TagRouteName("")
+	suite.Equal("user.show", tagFunc(router.Get("/users/{id}", HandlerTest).Name("user.show"), "/users/{id}"))
+}
+
+func (suite *TagsTestSuite) TestPackageName() {
+	suite.Equal("user", packageName("myapp/http/controller/user.(*Controller).Update-fm"))
+	suite.Equal("user", packageName("myapp/http/controller/user.Update"))
+	suite.Equal("main", packageName("main.handler"))
+}
+
+func (suite *TagsTestSuite) TestGenerateTags() {
+	router := goyave.NewRouter()
+	router.Get("/users", HandlerTest)
+	router.Get("/products", HandlerTest)
+	router.Get("/{id}", HandlerTest)
+
+	generator := NewGenerator()
+	generator.TagDescriptions = map[string]string{
+		"users":  "Users management",
+		"orders": "Orders management",
+	}
+	generator.TagGroups = []*TagGroup{
+		{Name: "Store", Tags: []string{"orders", "products"}},
+	}
+	spec := generator.Generate(router)
+
+	suite.Equal(openapi3.Tags{
+		{Name: "orders", Description: "Orders management"},
+		{Name: "products"},
+		{Name: "users", Description: "Users management"},
+	}, spec.Tags)
+
+	b, err := json.Marshal(spec.Extensions["x-tagGroups"])
+	suite.Nil(err)
+	suite.Equal(`[{"name":"Store","tags":["orders","products"]}]`, string(b))
+}
+
+func (suite *TagsTestSuite) TestGenerateTagFunc() {
+	router := goyave.NewRouter()
+	router.Get("/v1/users", HandlerTest)
+
+	generator := NewGenerator()
+	generator.TagFunc = TagFirstSegmentAfter("/v1")
+	spec := generator.Generate(router)
+	suite.Equal([]string{"users"}, spec.Paths["/v1/users"].Get.Tags)
+	suite.Len(spec.Tags, 1)
+	suite.Equal("users", spec.Tags[0].Name)
+	suite.NotContains(spec.Extensions, "x-tagGroups")
+}
+
+func (suite *TagsTestSuite) TestGenerateTagsPackageDoc() {
+	router := goyave.NewRouter()
+	router.Get("/users", user.Index)
+	router.Get("/test", HandlerTest)
+
+	generator := NewGenerator()
+	generator.TagFunc = TagControllerPackage
+	spec := generator.Generate(router)
+
+	suite.Equal([]string{"user"}, spec.Paths["/users"].Get.Tags)
+	suite.Equal(openapi3.Tags{
+		{Name: "openapi3"},
+		{Name: "user", Description: "Package user handles the users of the application.\n\nUsers can register, log in and update their profile."},
+	}, spec.Tags)
+	suite.Len(generator.Cache.PackageDocs, 2)
+
+	generator.TagDescriptions = map[string]string{"user": "Overridden"}
+	spec = generator.Generate(router)
+	suite.Equal("Overridden", spec.Tags.Get("user").Description)
+}
+
+func (suite *TagsTestSuite) TestGenerateTagsPackageDocDirectoryName() {
+	router := goyave.NewRouter()
+	router.Get("/products", product.Index)
+
+	generator := NewGenerator()
+	generator.TagFunc = TagControllerPackage
+	spec := generator.Generate(router)
+
+	// The tag uses the import path, the package is found using the handler's file
+	suite.Equal([]string{"products"}, spec.Paths["/products"].Get.Tags)
+	suite.Equal("Package product handles the products of the shop.", spec.Tags.Get("products").Description)
+}
+
+func (suite *TagsTestSuite) TestPackageDocMultipleFiles() {
+	dir := suite.T().TempDir()
+	files := map[string]string{
+		"a.go":      "// Package multi first comment.\npackage multi\n",
+		"b.go":      "// Package multi second comment.\npackage multi\n",
+		"c.go":      "package multi\n",
+		"c_test.go": "// Package multi test comment.\npackage multi\n",
+	}
+	for name, content := range files {
+		suite.Require().NoError(os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	// Same as go/doc: the comments of all the files are concatenated, in file name order
+	cache := NewCache()
+	suite.Equal("Package multi first comment.\n\nPackage multi second comment.", cache.packageDoc(filepath.Join(dir, "c.go")))
+}
+
+func (suite *TagsTestSuite) TestPackageDocUnreadable() {
+	cache := NewCache()
+	suite.Empty(cache.packageDoc("notadir/file.go"))
+	doc, ok := cache.getPackageDoc("notadir")
+	suite.True(ok)
+	suite.Empty(doc)
+}
+
+func TestTagsSuite(t *testing.T) {
+	if err := config.LoadJSON(`{"app":{"name": "Generator"}}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(TagsTestSuite))
+}
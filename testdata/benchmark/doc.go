@@ -0,0 +1,5 @@
+// Package benchmark contains a handful of handlers spread across several source
+// files, reused by the routes of the benchmarks of the generation of large specs.
+package benchmark
+
+//go:generate go run gen.go
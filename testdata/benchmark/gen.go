@@ -0,0 +1,70 @@
+//go:build ignore
+
+// This program generates the handlers of the benchmark package, spread across
+// several source files so the parallel source analysis can be measured. The
+// benchmark routes reuse these handlers.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+)
+
+const (
+	files           = 8
+	handlersPerFile = 2
+)
+
+func main() {
+	for f := 0; f < files; f++ {
+		buf := &bytes.Buffer{}
+		fmt.Fprintln(buf, "// Code generated by gen.go. DO NOT EDIT.")
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, "package benchmark")
+		fmt.Fprintln(buf)
+		fmt.Fprintln(buf, `import "goyave.dev/goyave/v4"`)
+		for h := 0; h < handlersPerFile; h++ {
+			name := handlerName(f, h)
+			fmt.Fprintln(buf)
+			fmt.Fprintf(buf, "// %s benchmark handler %d of file %d.\n", name, h, f)
+			fmt.Fprintln(buf, "//")
+			fmt.Fprintln(buf, "// The description of the handler spans multiple lines so the")
+			fmt.Fprintln(buf, "// documentation is not trivially short.")
+			fmt.Fprintf(buf, "func %s(_ *goyave.Response, _ *goyave.Request) {}\n", name)
+		}
+		write(fmt.Sprintf("handler%02d.go", f), buf.Bytes())
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "// Code generated by gen.go. DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package benchmark")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, `import "goyave.dev/goyave/v4"`)
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "// Handlers all the handlers of the package.")
+	fmt.Fprintln(buf, "var Handlers = []goyave.Handler{")
+	for f := 0; f < files; f++ {
+		for h := 0; h < handlersPerFile; h++ {
+			fmt.Fprintf(buf, "%s,\n", handlerName(f, h))
+		}
+	}
+	fmt.Fprintln(buf, "}")
+	write("handlers.go", buf.Bytes())
+}
+
+func handlerName(file, handler int) string {
+	return fmt.Sprintf("Handler%02d%d", file, handler)
+}
+
+func write(name string, src []byte) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile(name, formatted, 0644); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,17 @@
+// Code generated by gen.go. DO NOT EDIT.
+
+package benchmark
+
+import "goyave.dev/goyave/v4"
+
+// Handler000 benchmark handler 0 of file 0.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler000(_ *goyave.Response, _ *goyave.Request) {}
+
+// Handler001 benchmark handler 1 of file 0.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler001(_ *goyave.Response, _ *goyave.Request) {}
@@ -0,0 +1,17 @@
+// Code generated by gen.go. DO NOT EDIT.
+
+package benchmark
+
+import "goyave.dev/goyave/v4"
+
+// Handler010 benchmark handler 0 of file 1.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler010(_ *goyave.Response, _ *goyave.Request) {}
+
+// Handler011 benchmark handler 1 of file 1.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler011(_ *goyave.Response, _ *goyave.Request) {}
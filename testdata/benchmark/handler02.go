@@ -0,0 +1,17 @@
+// Code generated by gen.go. DO NOT EDIT.
+
+package benchmark
+
+import "goyave.dev/goyave/v4"
+
+// Handler020 benchmark handler 0 of file 2.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler020(_ *goyave.Response, _ *goyave.Request) {}
+
+// Handler021 benchmark handler 1 of file 2.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler021(_ *goyave.Response, _ *goyave.Request) {}
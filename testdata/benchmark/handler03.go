@@ -0,0 +1,17 @@
+// Code generated by gen.go. DO NOT EDIT.
+
+package benchmark
+
+import "goyave.dev/goyave/v4"
+
+// Handler030 benchmark handler 0 of file 3.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler030(_ *goyave.Response, _ *goyave.Request) {}
+
+// Handler031 benchmark handler 1 of file 3.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler031(_ *goyave.Response, _ *goyave.Request) {}
@@ -0,0 +1,17 @@
+// Code generated by gen.go. DO NOT EDIT.
+
+package benchmark
+
+import "goyave.dev/goyave/v4"
+
+// Handler040 benchmark handler 0 of file 4.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler040(_ *goyave.Response, _ *goyave.Request) {}
+
+// Handler041 benchmark handler 1 of file 4.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler041(_ *goyave.Response, _ *goyave.Request) {}
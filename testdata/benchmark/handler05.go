@@ -0,0 +1,17 @@
+// Code generated by gen.go. DO NOT EDIT.
+
+package benchmark
+
+import "goyave.dev/goyave/v4"
+
+// Handler050 benchmark handler 0 of file 5.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler050(_ *goyave.Response, _ *goyave.Request) {}
+
+// Handler051 benchmark handler 1 of file 5.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler051(_ *goyave.Response, _ *goyave.Request) {}
@@ -0,0 +1,17 @@
+// Code generated by gen.go. DO NOT EDIT.
+
+package benchmark
+
+import "goyave.dev/goyave/v4"
+
+// Handler060 benchmark handler 0 of file 6.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler060(_ *goyave.Response, _ *goyave.Request) {}
+
+// Handler061 benchmark handler 1 of file 6.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler061(_ *goyave.Response, _ *goyave.Request) {}
@@ -0,0 +1,17 @@
+// Code generated by gen.go. DO NOT EDIT.
+
+package benchmark
+
+import "goyave.dev/goyave/v4"
+
+// Handler070 benchmark handler 0 of file 7.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler070(_ *goyave.Response, _ *goyave.Request) {}
+
+// Handler071 benchmark handler 1 of file 7.
+//
+// The description of the handler spans multiple lines so the
+// documentation is not trivially short.
+func Handler071(_ *goyave.Response, _ *goyave.Request) {}
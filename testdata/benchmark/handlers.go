@@ -0,0 +1,25 @@
+// Code generated by gen.go. DO NOT EDIT.
+
+package benchmark
+
+import "goyave.dev/goyave/v4"
+
+// Handlers all the handlers of the package.
+var Handlers = []goyave.Handler{
+	Handler000,
+	Handler001,
+	Handler010,
+	Handler011,
+	Handler020,
+	Handler021,
+	Handler030,
+	Handler031,
+	Handler040,
+	Handler041,
+	Handler050,
+	Handler051,
+	Handler060,
+	Handler061,
+	Handler070,
+	Handler071,
+}
@@ -0,0 +1,13 @@
+// Package product handles the products of the shop.
+package product
+
+import (
+	"net/http"
+
+	"goyave.dev/goyave/v4"
+)
+
+// Index returns the list of products.
+func Index(response *goyave.Response, _ *goyave.Request) {
+	response.Status(http.StatusOK)
+}
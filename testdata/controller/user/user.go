@@ -0,0 +1,15 @@
+// Package user handles the users of the application.
+//
+// Users can register, log in and update their profile.
+package user
+
+import (
+	"net/http"
+
+	"goyave.dev/goyave/v4"
+)
+
+// Index returns the list of users.
+func Index(response *goyave.Response, _ *goyave.Request) {
+	response.Status(http.StatusOK)
+}
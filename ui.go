@@ -2,13 +2,22 @@ package openapi3
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"goyave.dev/goyave/v4"
 	"goyave.dev/goyave/v4/config"
+	"goyave.dev/goyave/v4/util/sliceutil"
 )
 
+var specFileNameFormat = regexp.MustCompile(`[^a-z0-9-_.]+`)
+
 // UIOptions options for the SwaggerUI Handler.
 type UIOptions struct {
 
@@ -29,6 +38,84 @@ type UIOptions struct {
 
 	// Spec JSON object
 	Spec string
+
+	// Specs named specs selectable in SwaggerUI's definition dropdown. Each spec
+	// is served at "{uri}/{name}.json". If not empty, `Spec` is ignored.
+	Specs []*UISpec
+	// PrimarySpec name of the spec selected by default. If empty, the first spec is selected.
+	PrimarySpec string
+
+	// DevMode if `true`, the spec is re-generated on every request when using ServeGenerated.
+	DevMode bool
+
+	// OAuth options passed to SwaggerUI's "initOAuth". If `nil`, "initOAuth" is not called.
+	OAuth *OAuthOptions
+}
+
+// UISpec a named spec displayed in SwaggerUI's definition dropdown.
+type UISpec struct {
+	// Name the name displayed in the dropdown. Must be unique, even ignoring case and
+	// punctuation: the spec is served at "<sanitized name>.json".
+	Name string
+	// Spec JSON object
+	Spec string
+}
+
+// NewUISpec create a new UISpec with the given name, containing the given spec.
+func NewUISpec(name string, spec *openapi3.T) *UISpec {
+	json, _ := spec.MarshalJSON()
+	return &UISpec{
+		Name: name,
+		Spec: string(json),
+	}
+}
+
+type uiSpecURL struct {
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+type uiTemplateData struct {
+	*UIOptions
+	URLs        string
+	PrimaryName string
+}
+
+// OAuthOptions options used by SwaggerUI to pre-fill the OAuth2 and OpenID Connect
+// authorization popup.
+type OAuthOptions struct {
+	// AdditionalQueryStringParams additional query parameters added to the authorization
+	// and token requests
+	AdditionalQueryStringParams map[string]string `json:"additionalQueryStringParams,omitempty"`
+
+	// ClientID default client ID
+	ClientID string `json:"clientId,omitempty"`
+	// ClientSecret default client secret. Never use this parameter in production.
+	ClientSecret string `json:"clientSecret,omitempty"`
+	// Realm realm query parameter added to the authorization and token URLs
+	Realm string `json:"realm,omitempty"`
+	// AppName application name, displayed in the authorization popup
+	AppName string `json:"appName,omitempty"`
+	// ScopeSeparator scope separator for passing scopes, before encoding. Defaults to a space.
+	ScopeSeparator string `json:"scopeSeparator,omitempty"`
+
+	// Scopes pre-selected scopes
+	Scopes []string `json:"scopes,omitempty"`
+
+	// UseBasicAuthenticationWithAccessCodeGrant send the client ID and secret using
+	// the HTTP Basic Authentication scheme for the "authorizationCode" flow
+	UseBasicAuthenticationWithAccessCodeGrant bool `json:"useBasicAuthenticationWithAccessCodeGrant,omitempty"`
+	// UsePKCEWithAuthorizationCodeGrant use the PKCE extension for the "authorizationCode" flow
+	UsePKCEWithAuthorizationCodeGrant bool `json:"usePkceWithAuthorizationCodeGrant,omitempty"`
+}
+
+// String returns the JSON representation of the options.
+func (o *OAuthOptions) String() string {
+	b, err := json.Marshal(o)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
 }
 
 const (
@@ -69,7 +156,8 @@ const (
     window.onload = function() {
       // Begin Swagger UI call region
       const ui = SwaggerUIBundle({
-        spec: {{ .Spec }},
+        {{ if .URLs }}urls: {{ .URLs }},
+        "urls.primaryName": {{ .PrimaryName }},{{ else }}spec: {{ .Spec }},{{ end }}
         dom_id: '#swagger-ui',
         deepLinking: true,
         presets: [
@@ -79,14 +167,101 @@ const (
         plugins: [
           SwaggerUIBundle.plugins.DownloadUrl
         ],
-        layout: "StandaloneLayout"
+        layout: "StandaloneLayout",
+        oauth2RedirectUrl: window.location.origin + window.location.pathname.replace(/\/$/, '') + '/oauth2-redirect.html'
       })
       // End Swagger UI call region
-      window.ui = ui
+      window.ui = ui{{ if .OAuth }}
+      ui.initOAuth({{ .OAuth }}){{ end }}
     }
   </script>
   </body>
 </html>
+`
+
+	// oauth2RedirectPage is copied from swagger-ui-dist's oauth2-redirect.html
+	// (https://github.com/swagger-api/swagger-ui/blob/master/dist/oauth2-redirect.html).
+	// Copyright 2020-2021 SmartBear Software Inc.
+	// Licensed under the Apache License, Version 2.0, found in the LICENSE-SWAGGER-UI file.
+	oauth2RedirectPage = `<!doctype html>
+<html lang="en-US">
+<head>
+    <title>Swagger UI: OAuth2 Redirect</title>
+</head>
+<body>
+<script>
+    'use strict';
+    function run () {
+        var oauth2 = window.opener.swaggerUIRedirectOauth2;
+        var sentState = oauth2.state;
+        var redirectUrl = oauth2.redirectUrl;
+        var isValid, qp, arr;
+
+        if (/code|token|error/.test(window.location.hash)) {
+            qp = window.location.hash.substring(1).replace('?', '&');
+        } else {
+            qp = location.search.substring(1);
+        }
+
+        arr = qp.split("&");
+        arr.forEach(function (v,i,_arr) { _arr[i] = '"' + v.replace('=', '":"') + '"';});
+        qp = qp ? JSON.parse('{' + arr.join() + '}',
+                function (key, value) {
+                    return key === "" ? value : decodeURIComponent(value);
+                }
+        ) : {};
+
+        isValid = qp.state === sentState;
+
+        if ((
+          oauth2.auth.schema.get("flow") === "accessCode" ||
+          oauth2.auth.schema.get("flow") === "authorizationCode" ||
+          oauth2.auth.schema.get("flow") === "authorization_code"
+        ) && !oauth2.auth.code) {
+            if (!isValid) {
+                oauth2.errCb({
+                    authId: oauth2.auth.name,
+                    source: "auth",
+                    level: "warning",
+                    message: "Authorization may be unsafe, passed state was changed in server. The passed state wasn't returned from auth server."
+                });
+            }
+
+            if (qp.code) {
+                delete oauth2.state;
+                oauth2.auth.code = qp.code;
+                oauth2.callback({auth: oauth2.auth, redirectUrl: redirectUrl});
+            } else {
+                let oauthErrorMsg;
+                if (qp.error) {
+                    oauthErrorMsg = "["+qp.error+"]: " +
+                        (qp.error_description ? qp.error_description+ ". " : "no accessCode received from the server. ") +
+                        (qp.error_uri ? "More info: "+qp.error_uri : "");
+                }
+
+                oauth2.errCb({
+                    authId: oauth2.auth.name,
+                    source: "auth",
+                    level: "error",
+                    message: oauthErrorMsg || "[Authorization failed]: no accessCode received from the server."
+                });
+            }
+        } else {
+            oauth2.callback({auth: oauth2.auth, token: qp, isValid: isValid, redirectUrl: redirectUrl});
+        }
+        window.close();
+    }
+
+    if (document.readyState !== 'loading') {
+        run();
+    } else {
+        document.addEventListener('DOMContentLoaded', function () {
+            run();
+        });
+    }
+</script>
+</body>
+</html>
 `
 )
 
@@ -97,6 +272,10 @@ const (
 //
 // The given spec can be `nil`, in which case, you'll have to set the returned
 // struct's `Spec` field to a valid JSON string.
+//
+// If the spec contains OAuth2 or OpenID Connect security schemes, the `OAuth` field
+// is initialized with the scopes required by the spec and PKCE enabled. You'll have to
+// set the client ID yourself.
 func NewUIOptions(spec *openapi3.T) *UIOptions {
 	var json []byte
 	if spec == nil {
@@ -112,27 +291,198 @@ func NewUIOptions(spec *openapi3.T) *UIOptions {
 		PresetURL: "https://unpkg.com/swagger-ui-dist/swagger-ui-standalone-preset.js",
 		StylesURL: "https://unpkg.com/swagger-ui-dist/swagger-ui.css",
 		Spec:      string(json),
+		OAuth:     newOAuthOptions(spec),
+	}
+}
+
+func newOAuthOptions(spec *openapi3.T) *OAuthOptions {
+	if spec == nil || spec.Components == nil {
+		return nil
+	}
+	hasOAuth := false
+	for _, s := range spec.Components.SecuritySchemes {
+		if s.Value != nil && (s.Value.Type == "oauth2" || s.Value.Type == "openIdConnect") {
+			hasOAuth = true
+			break
+		}
+	}
+	if !hasOAuth {
+		return nil
+	}
+
+	scopes := []string{}
+	for _, requirement := range spec.Security {
+		for _, s := range requirement {
+			for _, scope := range s {
+				if !sliceutil.ContainsStr(scopes, scope) {
+					scopes = append(scopes, scope)
+				}
+			}
+		}
+	}
+	return &OAuthOptions{
+		Scopes:                            scopes,
+		UsePKCEWithAuthorizationCodeGrant: true,
 	}
 }
 
 // Serve register the SwaggerUI route on the given router, with the given uri, and using
-// the given UIOptions.
-func Serve(router *goyave.Router, uri string, opts *UIOptions) {
+// the given UIOptions. If the given uri is empty, the "openapi.ui.path" config entry is used.
+// The given middleware are applied to the documentation routes. The subrouter containing
+// the documentation routes is returned.
+//
+// The OAuth2 redirect page used by SwaggerUI's "Authorize" button is served
+// at "{uri}/oauth2-redirect.html".
+//
+// If the "openapi.ui.enabled" config entry is `false`, no route is registered
+// and `nil` is returned.
+func Serve(router *goyave.Router, uri string, opts *UIOptions, middleware ...goyave.Middleware) *goyave.Router {
+	if !config.GetBool("openapi.ui.enabled") {
+		return nil
+	}
+	r := subrouter(router, uri, middleware)
+
+	b := renderUI(opts, serveSpecs(r, opts))
+	serveUI(r, func() ([]byte, error) {
+		return b, nil
+	})
+	return r
+}
+
+// ServeGenerated register the SwaggerUI route on the given router, with the given uri, and using
+// the given UIOptions. Unlike Serve, the spec is generated from the given router by the given
+// Generator on the first request and then cached. Therefore, routes registered after the call
+// to this function are documented too. The `Spec` field of the given UIOptions is ignored.
+//
+// If `opts.DevMode` is `true`, the spec is re-generated on every request. If the spec cannot
+// be generated, the error is returned in a 500 response.
+//
+// The documentation routes are excluded from the generated spec, without modifying the
+// given Generator. The uri, middleware and config entries are handled the same way as in Serve.
+func ServeGenerated(router *goyave.Router, uri string, generator *Generator, opts *UIOptions, middleware ...goyave.Middleware) *goyave.Router {
+	if !config.GetBool("openapi.ui.enabled") {
+		return nil
+	}
+	r := subrouter(router, uri, middleware)
+
+	mu := sync.Mutex{}
+	var b []byte
+	serveUI(r, func() ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if b == nil || opts.DevMode {
+			spec, err := generator.excluding(r).GenerateE(router)
+			if err != nil {
+				return nil, err
+			}
+			json, err := spec.MarshalJSON()
+			if err != nil {
+				return nil, err
+			}
+			o := *opts
+			o.Spec = string(json)
+			o.Specs = nil
+			if o.OAuth == nil {
+				o.OAuth = newOAuthOptions(spec)
+			}
+			b = renderUI(&o, nil)
+		}
+		return b, nil
+	})
+	return r
+}
+
+func subrouter(router *goyave.Router, uri string, middleware []goyave.Middleware) *goyave.Router {
+	if uri == "" {
+		uri = config.GetString("openapi.ui.path")
+	}
 	r := router.Subrouter(uri)
+	if len(middleware) != 0 {
+		r.Middleware(middleware...)
+	}
+	return r
+}
+
+func serveSpecs(r *goyave.Router, opts *UIOptions) []*uiSpecURL {
+	if len(opts.Specs) == 0 {
+		return nil
+	}
+	// Different names can have the same file name: one of the specs would be hidden
+	fileNames := make(map[string]string, len(opts.Specs))
+	for _, s := range opts.Specs {
+		fileName := specFileName(s.Name)
+		if other, ok := fileNames[fileName]; ok {
+			panic(fmt.Errorf("openapi3: UI specs %q and %q are both served as %q, use names that differ by more than case and punctuation", other, s.Name, fileName+".json"))
+		}
+		fileNames[fileName] = s.Name
+	}
+
+	urls := make([]*uiSpecURL, 0, len(opts.Specs))
+	for _, s := range opts.Specs {
+		json := []byte(s.Spec)
+		route := r.Get("/"+specFileName(s.Name)+".json", func(resp *goyave.Response, req *goyave.Request) {
+			resp.Header().Set("Content-Type", "application/json; charset=utf-8")
+			if _, err := resp.Write(json); err != nil {
+				panic(err)
+			}
+		})
+		urls = append(urls, &uiSpecURL{URL: route.BuildURI(), Name: s.Name})
+	}
+	return urls
+}
 
+func specFileName(name string) string {
+	return strings.Trim(specFileNameFormat.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func renderUI(opts *UIOptions, urls []*uiSpecURL) []byte {
 	tmpl := template.Must(template.New("swaggerui").Parse(uiTemplate))
 
+	data := &uiTemplateData{UIOptions: opts}
+	if len(urls) != 0 {
+		u, err := json.Marshal(urls)
+		if err != nil {
+			panic(err)
+		}
+		data.URLs = string(u)
+		primary := opts.PrimarySpec
+		if primary == "" {
+			primary = urls[0].Name
+		}
+		p, err := json.Marshal(primary)
+		if err != nil {
+			panic(err)
+		}
+		data.PrimaryName = string(p)
+	}
+
 	buf := bytes.NewBuffer(nil)
-	err := tmpl.Execute(buf, opts)
+	err := tmpl.Execute(buf, data)
 	if err != nil {
 		panic(err)
 	}
-	b := buf.Bytes()
+	return buf.Bytes()
+}
 
+// serveUI registers the SwaggerUI routes. If the page cannot be rendered, the
+// error is returned in a 500 response.
+func serveUI(r *goyave.Router, page func() ([]byte, error)) {
 	r.Get("/", func(resp *goyave.Response, req *goyave.Request) {
+		b, err := page()
+		if err != nil {
+			resp.String(http.StatusInternalServerError, err.Error())
+			return
+		}
 		resp.Header().Set("Content-Type", "text/html; charset=utf-8")
 		if _, err := resp.Write(b); err != nil {
 			panic(err)
 		}
 	})
+
+	r.Get("/oauth2-redirect.html", func(resp *goyave.Response, req *goyave.Request) {
+		resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if _, err := resp.Write([]byte(oauth2RedirectPage)); err != nil {
+			panic(err)
+		}
+	})
 }
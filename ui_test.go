@@ -1,6 +1,7 @@
 package openapi3
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -61,7 +62,8 @@ const (
         plugins: [
           SwaggerUIBundle.plugins.DownloadUrl
         ],
-        layout: "StandaloneLayout"
+        layout: "StandaloneLayout",
+        oauth2RedirectUrl: window.location.origin + window.location.pathname.replace(/\/$/, '') + '/oauth2-redirect.html'
       })
       // End Swagger UI call region
       window.ui = ui
@@ -96,6 +98,47 @@ func (suite *UITestSuite) TestNewOptionsNilSpec() {
 	opts := NewUIOptions(nil)
 	suite.NotNil(opts)
 	suite.Empty(opts.Spec)
+	suite.Nil(opts.OAuth)
+}
+
+func (suite *UITestSuite) TestNewOptionsOAuth() {
+	spec := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info: &openapi3.Info{
+			Title:   "Test",
+			Version: "0.0.0",
+		},
+		Paths: openapi3.Paths{},
+		Components: &openapi3.Components{
+			SecuritySchemes: openapi3.SecuritySchemes{
+				"oauth": &openapi3.SecuritySchemeRef{Value: NewOAuth2SecurityScheme(&openapi3.OAuthFlows{})},
+				"oidc":  &openapi3.SecuritySchemeRef{Value: openapi3.NewOIDCSecurityScheme("https://example.org")},
+			},
+		},
+		Security: openapi3.SecurityRequirements{
+			openapi3.NewSecurityRequirement().Authenticate("oauth", "read", "write"),
+			openapi3.NewSecurityRequirement().Authenticate("oidc", "read", "openid"),
+		},
+	}
+	opts := NewUIOptions(spec)
+	suite.NotNil(opts.OAuth)
+	suite.ElementsMatch([]string{"read", "write", "openid"}, opts.OAuth.Scopes)
+	suite.True(opts.OAuth.UsePKCEWithAuthorizationCodeGrant)
+
+	spec.Components.SecuritySchemes = openapi3.SecuritySchemes{
+		"jwt": &openapi3.SecuritySchemeRef{Value: openapi3.NewJWTSecurityScheme()},
+	}
+	opts = NewUIOptions(spec)
+	suite.Nil(opts.OAuth)
+}
+
+func (suite *UITestSuite) TestOAuthOptionsString() {
+	opts := &OAuthOptions{
+		ClientID:                          "client",
+		Scopes:                            []string{"read"},
+		UsePKCEWithAuthorizationCodeGrant: true,
+	}
+	suite.Equal(`{"clientId":"client","scopes":["read"],"usePkceWithAuthorizationCodeGrant":true}`, opts.String())
 }
 
 func (suite *UITestSuite) TestServe() {
@@ -123,8 +166,202 @@ func (suite *UITestSuite) TestServe() {
 
 			suite.Equal("text/html; charset=utf-8", resp.Header.Get("Content-Type"))
 		}
+
+		resp, err = suite.Get("/swaggerui/oauth2-redirect.html", nil)
+		suite.Nil(err)
+		if err == nil {
+			body := suite.GetBody(resp)
+			if err := resp.Body.Close(); err != nil {
+				suite.Fail(err.Error())
+			}
+			suite.Equal(oauth2RedirectPage, string(body))
+			suite.Equal("text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+		}
+	})
+}
+
+func (suite *UITestSuite) TestServeOAuth() {
+	opts := NewUIOptions(nil)
+	opts.Spec = "{}"
+	opts.OAuth = &OAuthOptions{ClientID: "client"}
+
+	suite.RunServer(func(r *goyave.Router) {
+		Serve(r, "/swaggerui", opts)
+	}, func() {
+		resp, err := suite.Get("/swaggerui", nil)
+		suite.Nil(err)
+		if err == nil {
+			body := suite.GetBody(resp)
+			if err := resp.Body.Close(); err != nil {
+				suite.Fail(err.Error())
+			}
+			suite.Contains(string(body), "      window.ui = ui\n      ui.initOAuth({\"clientId\":\"client\"})\n    }")
+		}
+	})
+}
+
+func (suite *UITestSuite) TestServeGenerated() {
+	opts := NewUIOptions(nil)
+	generator := NewGenerator()
+
+	suite.RunServer(func(r *goyave.Router) {
+		r.Get("/before", HandlerTest)
+		ServeGenerated(r, "/swaggerui", generator, opts)
+		r.Get("/after", HandlerTest)
+	}, func() {
+		resp, err := suite.Get("/swaggerui", nil)
+		suite.Nil(err)
+		if err == nil {
+			body := string(suite.GetBody(resp))
+			if err := resp.Body.Close(); err != nil {
+				suite.Fail(err.Error())
+			}
+			suite.Contains(body, `"/before":{`)
+			suite.Contains(body, `"/after":{`)
+			suite.NotContains(body, `"/swaggerui`)
+			suite.Equal("text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+		}
+	})
+	suite.Empty(opts.Spec)
+	suite.Empty(generator.excluded) // The generator is not modified
+}
+
+func (suite *UITestSuite) TestServeGeneratedError() {
+	generator := NewGenerator()
+	generator.Patches = []Patch{JSONPatch{{Op: "remove", Path: "/paths/~1unknown"}}}
+
+	suite.RunServer(func(r *goyave.Router) {
+		r.Get("/users", HandlerTest)
+		ServeGenerated(r, "/swaggerui", generator, NewUIOptions(nil))
+	}, func() {
+		resp, err := suite.Get("/swaggerui", nil)
+		suite.Nil(err)
+		if err == nil {
+			body := string(suite.GetBody(resp))
+			if err := resp.Body.Close(); err != nil {
+				suite.Fail(err.Error())
+			}
+			suite.Equal(http.StatusInternalServerError, resp.StatusCode)
+			suite.Contains(body, `JSON patch operation 0 (remove "/paths/~1unknown")`)
+		}
+	})
+}
+
+func (suite *UITestSuite) TestServeMiddleware() {
+	opts := NewUIOptions(nil)
+	opts.Spec = "{}"
+	middleware := func(next goyave.Handler) goyave.Handler {
+		return func(response *goyave.Response, request *goyave.Request) {
+			response.Status(http.StatusUnauthorized)
+		}
+	}
+
+	suite.RunServer(func(r *goyave.Router) {
+		suite.NotNil(Serve(r, "/swaggerui", opts, middleware))
+	}, func() {
+		resp, err := suite.Get("/swaggerui", nil)
+		suite.Nil(err)
+		if err == nil {
+			if err := resp.Body.Close(); err != nil {
+				suite.Fail(err.Error())
+			}
+			suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+		}
+	})
+}
+
+func (suite *UITestSuite) TestServeConfig() {
+	opts := NewUIOptions(nil)
+	opts.Spec = "{}"
+
+	suite.RunServer(func(r *goyave.Router) {
+		suite.NotNil(Serve(r, "", opts))
+	}, func() {
+		resp, err := suite.Get("/openapi", nil)
+		suite.Nil(err)
+		if err == nil {
+			if err := resp.Body.Close(); err != nil {
+				suite.Fail(err.Error())
+			}
+			suite.Equal(http.StatusOK, resp.StatusCode)
+		}
 	})
 
+	config.Set("openapi.ui.enabled", false)
+	defer config.Set("openapi.ui.enabled", true)
+	router := goyave.NewRouter()
+	suite.Nil(Serve(router, "/swaggerui", opts))
+	suite.Nil(ServeGenerated(router, "/swaggerui", NewGenerator(), opts))
+	suite.Empty(router.GetSubrouters())
+}
+
+func (suite *UITestSuite) TestNewUISpec() {
+	spec := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info: &openapi3.Info{
+			Title:   "Test",
+			Version: "0.0.0",
+		},
+		Paths: openapi3.Paths{},
+	}
+	s := NewUISpec("v1", spec)
+	suite.Equal("v1", s.Name)
+	suite.Equal(`{"info":{"title":"Test","version":"0.0.0"},"openapi":"3.0.0","paths":{}}`, s.Spec)
+}
+
+func (suite *UITestSuite) TestSpecFileName() {
+	suite.Equal("v1", specFileName("v1"))
+	suite.Equal("admin-api", specFileName("Admin API"))
+	suite.Equal("public-api_v1.2", specFileName("(Public API_v1.2)"))
+}
+
+func (suite *UITestSuite) TestServeSpecsDuplicateFileName() {
+	opts := NewUIOptions(nil)
+	opts.Specs = []*UISpec{{Name: "v1", Spec: "{}"}, {Name: "V1", Spec: "{}"}}
+	suite.PanicsWithError(`openapi3: UI specs "v1" and "V1" are both served as "v1.json", use names that differ by more than case and punctuation`, func() {
+		serveSpecs(goyave.NewRouter(), opts)
+	})
+
+	opts.Specs = []*UISpec{{Name: "api v1", Spec: "{}"}, {Name: "api-v1", Spec: "{}"}}
+	suite.Panics(func() {
+		serveSpecs(goyave.NewRouter(), opts)
+	})
+}
+
+func (suite *UITestSuite) TestServeSpecs() {
+	opts := NewUIOptions(nil)
+	opts.Specs = []*UISpec{
+		{Name: "v1", Spec: `{"info":{"title":"v1"}}`},
+		{Name: "Admin API", Spec: `{"info":{"title":"admin"}}`},
+	}
+	opts.PrimarySpec = "Admin API"
+
+	suite.RunServer(func(r *goyave.Router) {
+		Serve(r, "/swaggerui", opts)
+	}, func() {
+		resp, err := suite.Get("/swaggerui", nil)
+		suite.Nil(err)
+		if err == nil {
+			body := string(suite.GetBody(resp))
+			if err := resp.Body.Close(); err != nil {
+				suite.Fail(err.Error())
+			}
+			suite.Contains(body, `urls: [{"url":"/swaggerui/v1.json","name":"v1"},{"url":"/swaggerui/admin-api.json","name":"Admin API"}],`)
+			suite.Contains(body, `"urls.primaryName": "Admin API",`)
+			suite.NotContains(body, "spec: ")
+		}
+
+		resp, err = suite.Get("/swaggerui/admin-api.json", nil)
+		suite.Nil(err)
+		if err == nil {
+			body := string(suite.GetBody(resp))
+			if err := resp.Body.Close(); err != nil {
+				suite.Fail(err.Error())
+			}
+			suite.Equal(`{"info":{"title":"admin"}}`, body)
+			suite.Equal("application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+		}
+	})
 }
 
 func TestUISuite(t *testing.T) {
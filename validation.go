@@ -1,6 +1,7 @@
 package openapi3
 
 import (
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -13,7 +14,12 @@ import (
 )
 
 // ConvertToBody convert validation.Rules to OpenAPI RequestBody.
+// The properties are generated in alphabetical order, not in declaration order.
 func ConvertToBody(rules *validation.Rules) *openapi3.RequestBodyRef {
+	return convertToBody(rules, nil)
+}
+
+func convertToBody(rules *validation.Rules, plugins pluginList) *openapi3.RequestBodyRef {
 	if rules == nil {
 		return nil
 	}
@@ -23,15 +29,16 @@ func ConvertToBody(rules *validation.Rules) *openapi3.RequestBodyRef {
 	encodings := map[string]*openapi3.Encoding{}
 
 	schema := openapi3.NewObjectSchema()
-	for name, f := range rules.Fields {
-		field := f.(*validation.Field)
-		s, encoding := SchemaFromField(field)
+	for _, name := range sortedFieldNames(rules) {
+		field := rules.Fields[name].(*validation.Field)
+		s, encoding := generateSchema(field, "", plugins)
 		addSchema(field, field.Path, &openapi3.SchemaRef{Value: schema}, s)
 		if encoding != nil {
 			// TODO encoding should be ignored for objects
 			encodings[name] = encoding
 		}
 	}
+	normalizeRequired(schema)
 
 	content := newContent(rules, schema, encodings)
 	body := openapi3.NewRequestBody().WithContent(content)
@@ -65,7 +72,12 @@ func newContent(rules *validation.Rules, schema *openapi3.Schema, encodings map[
 }
 
 // ConvertToQuery convert validation.Rules to OpenAPI query Parameters.
+// The parameters are sorted by name, not in declaration order.
 func ConvertToQuery(rules *validation.Rules) []*openapi3.ParameterRef {
+	return convertToQuery(rules, nil)
+}
+
+func convertToQuery(rules *validation.Rules, plugins pluginList) []*openapi3.ParameterRef {
 	if rules == nil {
 		return nil
 	}
@@ -74,13 +86,21 @@ func ConvertToQuery(rules *validation.Rules) []*openapi3.ParameterRef {
 
 	tmpSchema := openapi3.NewObjectSchema()
 	parameters := make([]*openapi3.ParameterRef, 0, len(rules.Fields))
-	for _, f := range rules.Fields {
-		field := f.(*validation.Field)
-		s, _ := SchemaFromField(field)
+	for _, name := range sortedFieldNames(rules) {
+		field := rules.Fields[name].(*validation.Field)
+		s, _ := generateSchema(field, "", plugins)
 		addSchema(field, field.Path, &openapi3.SchemaRef{Value: tmpSchema}, s)
 	}
+	normalizeRequired(tmpSchema)
 
-	for name, s := range tmpSchema.Properties {
+	names := make([]string, 0, len(tmpSchema.Properties))
+	for name := range tmpSchema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := tmpSchema.Properties[name]
 		param := openapi3.NewQueryParameter(name)
 		param.Schema = s
 		format := param.Schema.Value.Format
@@ -93,12 +113,47 @@ func ConvertToQuery(rules *validation.Rules) []*openapi3.ParameterRef {
 	return parameters
 }
 
+// sortedFieldNames returns the names of the fields of the given rules in
+// alphabetical order so the generated spec is deterministic. The declaration
+// order of the fields cannot be recovered: goyave stores them in a map.
+func sortedFieldNames(rules *validation.Rules) []string {
+	names := make([]string, 0, len(rules.Fields))
+	for name := range rules.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// normalizeRequired sorts and removes duplicates from the "required" lists of the given
+// schema, its properties and its items, recursively.
+func normalizeRequired(schema *openapi3.Schema) {
+	if len(schema.Required) != 0 {
+		sort.Strings(schema.Required)
+		required := schema.Required[:1]
+		for _, r := range schema.Required[1:] {
+			if r != required[len(required)-1] {
+				required = append(required, r)
+			}
+		}
+		schema.Required = required
+	}
+	for _, p := range schema.Properties {
+		if p.Value != nil {
+			normalizeRequired(p.Value)
+		}
+	}
+	if schema.Items != nil && schema.Items.Value != nil {
+		normalizeRequired(schema.Items.Value)
+	}
+}
+
 // SchemaFromField convert a validation.Field to OpenAPI Schema.
 func SchemaFromField(field *validation.Field) (*openapi3.Schema, *openapi3.Encoding) {
-	return generateSchema(field, "")
+	return generateSchema(field, "", nil)
 }
 
-func generateSchema(field *validation.Field, typeFallback string) (*openapi3.Schema, *openapi3.Encoding) {
+func generateSchema(field *validation.Field, typeFallback string, plugins pluginList) (*openapi3.Schema, *openapi3.Encoding) {
 	s := openapi3.NewSchema()
 	if rule := findFirstTypeRule(field); rule != nil {
 		switch rule.Name {
@@ -116,7 +171,7 @@ func generateSchema(field *validation.Field, typeFallback string) (*openapi3.Sch
 				itemsType = ruleNameToType(rule.Params[0])
 			}
 			if field.Elements != nil {
-				items, _ := generateSchema(field.Elements, itemsType)
+				items, _ := generateSchema(field.Elements, itemsType, plugins)
 				s.Items = &openapi3.SchemaRef{Value: items}
 			} else {
 				s.Items = &openapi3.SchemaRef{Value: &openapi3.Schema{Type: itemsType}}
@@ -139,6 +194,7 @@ func generateSchema(field *validation.Field, typeFallback string) (*openapi3.Sch
 	}
 
 	s.Nullable = field.IsNullable()
+	plugins.schemaProperty(field, s)
 	return s, encoding
 }
 
@@ -801,6 +801,77 @@ func (suite *ValidationTestSuite) TestConvertToQuery() {
 	suite.False(field2.Value.Required)
 }
 
+func (suite *ValidationTestSuite) TestConvertToBodyDeterministic() {
+	rules := &validation.Rules{
+		Fields: validation.FieldMap{
+			"c": &validation.Field{Rules: []*validation.Rule{
+				{Name: "required"},
+				{Name: "string"},
+			}},
+			"a": &validation.Field{Rules: []*validation.Rule{
+				{Name: "required"},
+				{Name: "numeric"},
+			}},
+			"b": &validation.Field{Rules: []*validation.Rule{
+				{Name: "required"},
+				{Name: "object"},
+			}},
+			"b.z": &validation.Field{Rules: []*validation.Rule{
+				{Name: "required"},
+				{Name: "string"},
+			}},
+			"b.y": &validation.Field{Rules: []*validation.Rule{
+				{Name: "required"},
+				{Name: "string"},
+			}},
+		},
+	}
+
+	for i := 0; i < 10; i++ {
+		bodyRef := ConvertToBody(rules)
+		schema := bodyRef.Value.Content["application/json"].Schema.Value
+		suite.Equal([]string{"a", "b", "c"}, schema.Required)
+		suite.Equal([]string{"y", "z"}, schema.Properties["b"].Value.Required)
+	}
+}
+
+func (suite *ValidationTestSuite) TestConvertToQueryDeterministic() {
+	rules := &validation.Rules{
+		Fields: validation.FieldMap{
+			"c": &validation.Field{Rules: []*validation.Rule{
+				{Name: "string"},
+			}},
+			"a": &validation.Field{Rules: []*validation.Rule{
+				{Name: "numeric"},
+			}},
+			"b": &validation.Field{Rules: []*validation.Rule{
+				{Name: "bool"},
+			}},
+		},
+	}
+
+	for i := 0; i < 10; i++ {
+		query := ConvertToQuery(rules)
+		names := make([]string, 0, len(query))
+		for _, p := range query {
+			names = append(names, p.Value.Name)
+		}
+		suite.Equal([]string{"a", "b", "c"}, names)
+	}
+}
+
+func (suite *ValidationTestSuite) TestNormalizeRequired() {
+	schema := openapi3.NewObjectSchema()
+	schema.Required = []string{"b", "a", "b"}
+	items := openapi3.NewObjectSchema()
+	items.Required = []string{"d", "c", "c"}
+	schema.Properties["array"] = openapi3.NewArraySchema().WithItems(items).NewRef()
+
+	normalizeRequired(schema)
+	suite.Equal([]string{"a", "b"}, schema.Required)
+	suite.Equal([]string{"c", "d"}, items.Required)
+}
+
 func findParam(query []*openapi3.ParameterRef, name string) *openapi3.ParameterRef {
 	for _, v := range query {
 		if v.Value.Name == name {
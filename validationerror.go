@@ -0,0 +1,158 @@
+package openapi3
+
+import (
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"goyave.dev/goyave/v4/util/walk"
+	"goyave.dev/goyave/v4/validation"
+)
+
+const (
+	// ValidationErrorSchema name of the schema component describing goyave's
+	// validation error response body.
+	ValidationErrorSchema = "validationError"
+
+	// FieldErrorsSchema name of the schema component describing the validation
+	// errors of a single field.
+	FieldErrorsSchema = "validationFieldErrors"
+
+	validationErrorSuffix = "-validationError"
+)
+
+// ValidationErrorSchemas returns the schema components describing goyave's validation
+// error response body: the "validationError" object associates each invalid field
+// to its errors. The errors of the fields of an object are in "fields" and the errors
+// of the elements of an array are in "elements", indexed by element index.
+func ValidationErrorSchemas() openapi3.Schemas {
+	fieldErrorsRef := &openapi3.SchemaRef{Ref: "#/components/schemas/" + FieldErrorsSchema}
+
+	fieldErrors := openapi3.NewObjectSchema()
+	fieldErrors.Description = "Validation errors of a field."
+	errors := openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema())
+	errors.Description = "Error messages of the field itself."
+	fieldErrors.Properties["errors"] = errors.NewRef()
+	fieldErrors.Properties["fields"] = newMapSchema(fieldErrorsRef, "Validation errors of the object's fields, indexed by field name.").NewRef()
+	fieldErrors.Properties["elements"] = newMapSchema(fieldErrorsRef, "Validation errors of the array's elements, indexed by element index.").NewRef()
+
+	body := openapi3.NewObjectSchema()
+	body.Properties[ValidationErrorSchema] = newMapSchema(fieldErrorsRef, "Validation errors indexed by field name.").NewRef()
+	body.Required = []string{ValidationErrorSchema}
+
+	return openapi3.Schemas{
+		FieldErrorsSchema:     fieldErrors.NewRef(),
+		ValidationErrorSchema: body.NewRef(),
+	}
+}
+
+// ConvertToValidationError convert validation.Rules to the schema of the validation
+// error response body returned when the request doesn't pass validation. The schema
+// extends the "validationError" component (see ValidationErrorSchemas) and lists the
+// fields of the rule set.
+func ConvertToValidationError(rules *validation.Rules) *openapi3.SchemaRef {
+	if rules == nil {
+		return nil
+	}
+
+	rules = rules.AsRules() // Ensure rules are checked
+
+	errors := openapi3.NewObjectSchema()
+	for _, name := range sortedFieldNames(rules) {
+		field := rules.Fields[name].(*validation.Field)
+		addErrorSchema(field.Path, errors)
+	}
+
+	body := openapi3.NewObjectSchema()
+	body.Properties[ValidationErrorSchema] = errors.NewRef()
+	schema := &openapi3.Schema{
+		AllOf: openapi3.SchemaRefs{
+			{Ref: "#/components/schemas/" + ValidationErrorSchema},
+			body.NewRef(),
+		},
+	}
+	return schema.NewRef()
+}
+
+// newMapSchema returns an object schema whose additional properties are described
+// by the given schema.
+func newMapSchema(values *openapi3.SchemaRef, description string) *openapi3.Schema {
+	s := openapi3.NewObjectSchema()
+	s.Description = description
+	s.AdditionalProperties = openapi3.AdditionalProperties{Schema: values}
+	return s
+}
+
+// newFieldErrorsSchema returns the schema of the errors of a field without nested
+// fields or elements.
+func newFieldErrorsSchema() *openapi3.Schema {
+	s := openapi3.NewObjectSchema()
+	s.Properties["errors"] = openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema()).NewRef()
+	return s
+}
+
+// addErrorSchema adds the errors of the field identified by the given path to
+// the given object schema, whose properties are the field names.
+func addErrorSchema(path *walk.Path, fields *openapi3.Schema) {
+	ref, ok := fields.Properties[path.Name]
+	if !ok {
+		ref = newFieldErrorsSchema().NewRef()
+		fields.Properties[path.Name] = ref
+	}
+	addNestedErrorSchema(path, ref.Value)
+}
+
+func addNestedErrorSchema(path *walk.Path, fieldErrors *openapi3.Schema) {
+	switch path.Type {
+	case walk.PathTypeArray:
+		elements, ok := fieldErrors.Properties["elements"]
+		if !ok {
+			elements = openapi3.NewObjectSchema().WithAdditionalProperties(newFieldErrorsSchema()).NewRef()
+			fieldErrors.Properties["elements"] = elements
+		}
+		addNestedErrorSchema(path.Next, elements.Value.AdditionalProperties.Schema.Value)
+	case walk.PathTypeObject:
+		fields, ok := fieldErrors.Properties["fields"]
+		if !ok {
+			fields = openapi3.NewObjectSchema().NewRef()
+			fieldErrors.Properties["fields"] = fields
+		}
+		addErrorSchema(path.Next, fields.Value)
+	}
+}
+
+// convertValidationError returns the 422 response of the route, whose schema is
+// generated once per rule set and stored in the components.
+func (c *RouteConverter) convertValidationError(rules *validation.Rules, spec *openapi3.T) *openapi3.ResponseRef {
+	for name, schema := range ValidationErrorSchemas() {
+		if _, exists := spec.Components.Schemas[name]; !exists {
+			spec.Components.Schemas[name] = schema
+		}
+	}
+
+	schemaRef, ok := c.refs.ValidationErrors[rules]
+	if !ok {
+		// Different rule sets can have the same name if they are used by the same handler.
+		// goyave creates a new rule set for each route: identical schemas are shared.
+		schema := ConvertToValidationError(rules)
+		baseName := c.rulesRefName() + validationErrorSuffix
+		refName := baseName
+		for i := 2; ; i++ {
+			existing, exists := spec.Components.Schemas[refName]
+			if !exists {
+				spec.Components.Schemas[refName] = schema
+				break
+			}
+			if jsonEqual(existing, schema) {
+				break
+			}
+			refName = fmt.Sprintf("%s.%d", baseName, i)
+		}
+		schemaRef = &openapi3.SchemaRef{Ref: "#/components/schemas/" + refName}
+		c.refs.ValidationErrors[rules] = schemaRef
+	}
+
+	response := openapi3.NewResponse().
+		WithDescription("The request didn't pass validation.").
+		WithContent(openapi3.NewContentWithJSONSchemaRef(schemaRef))
+	return &openapi3.ResponseRef{Value: response}
+}
@@ -0,0 +1,151 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v4"
+	"goyave.dev/goyave/v4/config"
+	"goyave.dev/goyave/v4/validation"
+)
+
+type ValidationErrorTestSuite struct {
+	goyave.TestSuite
+}
+
+func (suite *ValidationErrorTestSuite) TestValidationErrorSchemas() {
+	schemas := ValidationErrorSchemas()
+	suite.Len(schemas, 2)
+
+	fieldErrors := schemas[FieldErrorsSchema].Value
+	suite.Equal("array", fieldErrors.Properties["errors"].Value.Type)
+	suite.Equal("string", fieldErrors.Properties["errors"].Value.Items.Value.Type)
+	suite.Equal("#/components/schemas/validationFieldErrors", fieldErrors.Properties["fields"].Value.AdditionalProperties.Schema.Ref)
+	suite.Equal("#/components/schemas/validationFieldErrors", fieldErrors.Properties["elements"].Value.AdditionalProperties.Schema.Ref)
+
+	body := schemas[ValidationErrorSchema].Value
+	suite.Equal([]string{"validationError"}, body.Required)
+	suite.Equal("#/components/schemas/validationFieldErrors", body.Properties["validationError"].Value.AdditionalProperties.Schema.Ref)
+
+	// The schemas must be valid once added to a spec
+	spec := &openapi3.T{
+		OpenAPI:    "3.0.0",
+		Info:       &openapi3.Info{Title: "test", Version: "0.0.0"},
+		Paths:      openapi3.Paths{},
+		Components: &openapi3.Components{Schemas: schemas},
+	}
+	b, err := spec.MarshalJSON()
+	suite.Require().NoError(err)
+	loaded, err := openapi3.NewLoader().LoadFromData(b)
+	suite.Require().NoError(err)
+	suite.Nil(loaded.Validate(openapi3.NewLoader().Context))
+}
+
+func (suite *ValidationErrorTestSuite) TestConvertToValidationError() {
+	suite.Nil(ConvertToValidationError(nil))
+
+	rules := (validation.RuleSet{
+		"name":             validation.List{"required", "string"},
+		"user":             validation.List{"required", "object"},
+		"user.email":       validation.List{"required", "email"},
+		"user.address":     validation.List{"object"},
+		"user.address.zip": validation.List{"string"},
+		"tags":             validation.List{"array"},
+		"tags[]":           validation.List{"string"},
+		"items":            validation.List{"array"},
+		"items[].id":       validation.List{"integer"},
+		"matrix[][]":       validation.List{"numeric"},
+	}).AsRules()
+
+	schema := ConvertToValidationError(rules).Value
+	suite.Require().Len(schema.AllOf, 2)
+	suite.Equal("#/components/schemas/validationError", schema.AllOf[0].Ref)
+
+	errors := schema.AllOf[1].Value.Properties["validationError"].Value
+	suite.Len(errors.Properties, 5)
+	suite.Contains(errors.Properties, "name")
+	suite.Contains(errors.Properties["name"].Value.Properties, "errors")
+	suite.NotContains(errors.Properties["name"].Value.Properties, "fields")
+
+	user := errors.Properties["user"].Value
+	suite.Contains(user.Properties, "errors")
+	userFields := user.Properties["fields"].Value
+	suite.Len(userFields.Properties, 2)
+	suite.Contains(userFields.Properties, "email")
+	address := userFields.Properties["address"].Value
+	suite.Contains(address.Properties["fields"].Value.Properties, "zip")
+
+	tags := errors.Properties["tags"].Value
+	suite.NotContains(tags.Properties, "fields")
+	suite.Contains(tags.Properties["elements"].Value.AdditionalProperties.Schema.Value.Properties, "errors")
+
+	item := errors.Properties["items"].Value.Properties["elements"].Value.AdditionalProperties.Schema.Value
+	suite.Contains(item.Properties["fields"].Value.Properties, "id")
+
+	matrix := errors.Properties["matrix"].Value.Properties["elements"].Value.AdditionalProperties.Schema.Value
+	suite.Contains(matrix.Properties, "elements")
+}
+
+func (suite *ValidationErrorTestSuite) TestGenerateValidationError() {
+	rules := validation.RuleSet{
+		"name": validation.List{"required", "string"},
+	}
+	router := goyave.NewRouter()
+	router.Post("/users", HandlerTest).Validate(rules)
+	router.Get("/users", HandlerTest).Validate(rules)
+	router.Get("/products", HandlerTest)
+
+	spec := NewGenerator().Generate(router)
+	suite.Contains(spec.Components.Schemas, ValidationErrorSchema)
+	suite.Contains(spec.Components.Schemas, FieldErrorsSchema)
+	suite.Contains(spec.Components.Schemas, "HandlerTest-validationError")
+
+	post := spec.Paths["/users"].Post.Responses["422"]
+	suite.Require().NotNil(post)
+	suite.Equal("#/components/schemas/HandlerTest-validationError", post.Value.Content["application/json"].Schema.Ref)
+	get := spec.Paths["/users"].Get.Responses["422"]
+	suite.Require().NotNil(get)
+	// Each route has its own rule set, generated from the same RuleSet: the schema is shared
+	suite.Equal("#/components/schemas/HandlerTest-validationError", get.Value.Content["application/json"].Schema.Ref)
+	suite.NotContains(spec.Paths["/products"].Get.Responses, "422")
+
+	errors := spec.Components.Schemas["HandlerTest-validationError"].Value.AllOf[1].Value.Properties["validationError"].Value
+	suite.Contains(errors.Properties, "name")
+	suite.NotContains(spec.Components.Schemas, "HandlerTest-validationError.2")
+}
+
+func (suite *ValidationErrorTestSuite) TestGenerateValidationErrorDuplicateName() {
+	router := goyave.NewRouter()
+	router.Post("/users", HandlerTest).Validate(validation.RuleSet{
+		"name": validation.List{"required", "string"},
+	})
+	router.Put("/users", HandlerTest).Validate(validation.RuleSet{
+		"email": validation.List{"required", "email"},
+	})
+
+	spec := NewGenerator().Generate(router)
+	post := spec.Paths["/users"].Post.Responses["422"]
+	suite.Equal("#/components/schemas/HandlerTest-validationError", post.Value.Content["application/json"].Schema.Ref)
+	put := spec.Paths["/users"].Put.Responses["422"]
+	suite.Equal("#/components/schemas/HandlerTest-validationError.2", put.Value.Content["application/json"].Schema.Ref)
+
+	errors := spec.Components.Schemas["HandlerTest-validationError.2"].Value.AllOf[1].Value.Properties["validationError"].Value
+	suite.Contains(errors.Properties, "email")
+}
+
+func TestValidationErrorSuite(t *testing.T) {
+	if err := config.LoadJSON(`{
+			"app": {
+				"name": "Generator"
+			},
+			"server": {
+				"protocol": "http",
+				"domain": "goyave.dev",
+				"port": 80
+			}
+		}`); err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	goyave.RunTest(t, new(ValidationErrorTestSuite))
+}